@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/server"
+	"github.com/yesahem/burnenv/internal/ui"
+)
+
+var (
+	apiKeyName      string
+	apiKeyMaxExpiry time.Duration
+	apiKeyMaxViews  int
+	apiKeyMaxSize   int
+	apiKeyRate      int
+)
+
+var apiKeyCmd = &cobra.Command{
+	Use:   "apikey",
+	Short: "Manage API keys for burnenv serve --api-keys",
+}
+
+var apiKeyNewCmd = &cobra.Command{
+	Use:   "new",
+	Short: "Generate an API key and its keys-file entry",
+	Long: `Generates a random API key. The key is printed once on stdout; add the
+printed entry (which contains only its SHA-256) to the server's keys file.
+Clients send the key via BURNENV_API_KEY or "api_key" in the config file.`,
+	RunE: runAPIKeyNew,
+}
+
+func init() {
+	rootCmd.AddCommand(apiKeyCmd)
+	apiKeyCmd.AddCommand(apiKeyNewCmd)
+	apiKeyNewCmd.Flags().StringVar(&apiKeyName, "name", "", "Key name (shown in audit logs)")
+	apiKeyNewCmd.Flags().DurationVar(&apiKeyMaxExpiry, "max-expiry", 0, "Maximum expiry for drops created with this key (0 = server limit)")
+	apiKeyNewCmd.Flags().IntVar(&apiKeyMaxViews, "max-views", 0, "Maximum views per drop (0 = server limit)")
+	apiKeyNewCmd.Flags().IntVar(&apiKeyMaxSize, "max-size", 0, "Maximum ciphertext size in bytes (0 = server limit)")
+	apiKeyNewCmd.Flags().IntVar(&apiKeyRate, "rate", 0, "Maximum creates per minute (0 = unlimited)")
+	apiKeyNewCmd.MarkFlagRequired("name")
+}
+
+func runAPIKeyNew(cmd *cobra.Command, args []string) error {
+	b := make([]byte, 32)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	key := "bek_" + base64.RawURLEncoding.EncodeToString(b)
+	entry := server.KeyEntry{
+		Name:   apiKeyName,
+		SHA256: server.HashAPIKey(key),
+		Policy: server.KeyPolicy{
+			MaxExpirySeconds: int64(apiKeyMaxExpiry / time.Second),
+			MaxViews:         apiKeyMaxViews,
+			MaxSize:          apiKeyMaxSize,
+			RatePerMinute:    apiKeyRate,
+		},
+	}
+
+	if jsonOutput {
+		out := struct {
+			Key   string          `json:"key"`
+			Entry server.KeyEntry `json:"entry"`
+		}{Key: key, Entry: entry}
+		return json.NewEncoder(os.Stdout).Encode(out)
+	}
+
+	fmt.Println(key)
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	fmt.Fprintln(os.Stderr, ui.Success.Render("✓ API key generated (shown once). Add this entry to the server keys file:"))
+	fmt.Fprintln(os.Stderr, string(data))
+	return nil
+}
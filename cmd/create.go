@@ -2,51 +2,119 @@ package cmd
 
 import (
 	"bufio"
+	"bytes"
 	"encoding/json"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/archive"
 	"github.com/yesahem/burnenv/internal/client"
 	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/dotenv"
+	"github.com/yesahem/burnenv/internal/expiry"
+	"github.com/yesahem/burnenv/internal/passphrase"
 	"github.com/yesahem/burnenv/internal/store"
 	"github.com/yesahem/burnenv/internal/ui"
 	"golang.org/x/term"
 )
 
 var (
-	expiryMinutes int
+	createExpiry  string
 	maxViews      int
 	password      string
 	serverURL     string
 	useTUI        bool
+	validateEnv   bool
+	createFrom    string
+	createOnly    []string
+	createExclude []string
+	createFiles   []string
+	createDirs    []string
+	createMaxSize int
+	createTrim    bool
+	createGenPW   bool
+	createWords   int
+	createMinStr  int
 )
 
 func init() {
 	rootCmd.AddCommand(createCmd)
-	createCmd.Flags().IntVar(&expiryMinutes, "expiry", 3, "Expiry in minutes (2-10)")
+	createCmd.Flags().StringVar(&createExpiry, "expiry", "3m", "Expiry as a duration (90s, 45m, 6h; bare numbers are minutes) or a time (2026-10-17T09:00Z, 09:00); range advertised by the server, 2-10m for local mock")
 	createCmd.Flags().IntVar(&maxViews, "max-views", 1, "Maximum number of views before destruction")
-	createCmd.Flags().StringVar(&password, "password", "", "Password (prefer BURNENV_PASSWORD env; avoid passing on CLI)")
+	createCmd.Flags().StringVar(&password, "password", "", "Password (prefer --password-file, --password-fd or --password-cmd; avoid passing on CLI)")
+	addPasswordFlags(createCmd)
+	createCmd.Flags().BoolVar(&createGenPW, "generate-password", false, "Generate a diceware passphrase (EFF wordlist) and print it to stderr")
+	createCmd.Flags().IntVar(&createWords, "words", passphrase.DefaultWords, "Words in the --generate-password passphrase")
+	createCmd.Flags().IntVar(&createMinStr, "min-strength", 0, "Refuse passwords scoring below this (0-4: "+strings.Join(passphrase.Labels, ", ")+")")
 	createCmd.Flags().StringVar(&serverURL, "server", "", "Server base URL (e.g. http://localhost:8080). Omit for local mock.")
 	createCmd.Flags().BoolVar(&useTUI, "tui", false, "Use interactive TUI mode")
+	createCmd.Flags().StringVar(&createFrom, "from", "", "Read the secret from this file instead of stdin")
+	createCmd.Flags().StringArrayVar(&createFiles, "file", nil, "Share this file (repeatable); names and permissions are kept")
+	createCmd.Flags().StringArrayVar(&createDirs, "dir", nil, "Share this directory recursively (repeatable)")
+	createCmd.Flags().StringSliceVar(&createOnly, "only", nil, "Share only these .env keys (comma-separated; globs like STRIPE_* allowed)")
+	createCmd.Flags().StringSliceVar(&createExclude, "exclude", nil, "Leave out these .env keys (comma-separated; globs allowed)")
+	createCmd.Flags().IntVar(&createMaxSize, "max-size", 0, "Refuse secrets over this many bytes (default and upper bound: the server's limit)")
+	createCmd.Flags().BoolVar(&createTrim, "trim", false, "Remove one trailing newline from stdin or --from (input is otherwise kept byte for byte)")
+	createCmd.Flags().BoolVar(&validateEnv, "validate", false, "Check the secret is a valid .env and warn about duplicate keys, empty values and invalid keys")
 }
 
 var createCmd = &cobra.Command{
 	Use:   "create",
 	Short: "Create a burn link from secret data",
 	Long: `Reads secret from STDIN or interactive prompt.
-Encrypts locally and sends only ciphertext to the server.`,
+Encrypts locally and sends only ciphertext to the server.
+
+Piped input is shared byte for byte, so binary files such as keystores
+survive intact; --trim drops the trailing newline echo and editors add.
+Secrets over --max-size (by default the server's limit) are refused
+before anything is sent.
+
+With --file and --dir, files are packed into a tar archive (names and
+permissions kept) and shared instead; recipients restore them with
+burnenv open --extract-to <dir>.`,
 	RunE: runCreate,
 }
 
 func runCreate(cmd *cobra.Command, args []string) error {
+	url := serverURL
+	if url == "" {
+		url = os.Getenv("BURNENV_SERVER")
+	}
+
+	// Validate options against what the target server permits
+	exp, err := expiry.Parse(createExpiry, time.Now())
+	if err != nil {
+		return withCode(apierr.Usage, err)
+	}
+	limits := client.LimitsFor(url)
+	if err := checkCreateLimits(limits, exp, url != ""); err != nil {
+		return withCode(apierr.Usage, err)
+	}
+	if err := checkPasswordOptions(); err != nil {
+		return withCode(apierr.Usage, err)
+	}
+	if err := checkTUIOptions(); err != nil {
+		return withCode(apierr.Usage, err)
+	}
+	maxSize := limits.MaxSecretBytes()
+	if createMaxSize > 0 {
+		maxSize = min(maxSize, createMaxSize)
+	}
+
 	// TUI mode: interactive only, skip when piping or --json
 	stat, _ := os.Stdin.Stat()
 	isInteractive := (stat.Mode() & os.ModeCharDevice) != 0
+	sharingFiles := len(createFiles) > 0 || len(createDirs) > 0
+	if sharingFiles && (createFrom != "" || len(createOnly) > 0 || len(createExclude) > 0 || validateEnv) {
+		return withCode(apierr.Usage, fmt.Errorf("--file/--dir cannot be combined with --from, --only, --exclude or --validate"))
+	}
 	if useTUI && isInteractive && !jsonOutput {
-		link, err := ui.RunCreateTUI(expiryMinutes, maxViews, serverURL)
+		link, err := ui.RunCreateTUI(exp, maxViews, createMinStr, serverURL)
 		if err != nil {
 			return err
 		}
@@ -55,57 +123,83 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
-	// Read secret: STDIN if available, else interactive
-	secret, err := readSecret()
+	// Read secret: files to pack, --from file, else STDIN if available, else interactive
+	var secret []byte
+	var contentType string
+	switch {
+	case sharingFiles:
+		if secret, err = archive.Pack(createFiles, createDirs); err == nil {
+			contentType = crypto.ContentTypeTar
+			err = describeArchive(secret)
+		}
+	case createFrom != "":
+		secret, err = readLimited(createFrom, maxSize)
+	default:
+		secret, err = readSecret(maxSize)
+	}
 	if err != nil {
 		return err
 	}
+	if createTrim && !sharingFiles {
+		secret = trimNewline(secret)
+	}
+	if len(secret) > maxSize {
+		return withCode(apierr.TooLarge, fmt.Errorf("secret is larger than the %d byte limit", maxSize))
+	}
+	if len(createOnly) > 0 || len(createExclude) > 0 {
+		if secret, err = selectKeys(secret); err != nil {
+			return err
+		}
+	}
 	if len(secret) == 0 {
 		return fmt.Errorf("secret cannot be empty")
 	}
-
-	// Validate and get options
-	if expiryMinutes < 2 || expiryMinutes > 10 {
-		return fmt.Errorf("expiry must be between 2 and 10 minutes")
-	}
-	if maxViews < 1 {
-		maxViews = 1
+	var warnings []string
+	if validateEnv {
+		if warnings, err = lintSecret(secret); err != nil {
+			return err
+		}
 	}
 
-	if password == "" {
-		password = os.Getenv("BURNENV_PASSWORD")
-	}
-	if password == "" {
-		pw, err := promptPassword("Password: ")
+	var pw []byte
+	if createGenPW {
+		phrase, err := passphrase.Generate(createWords)
 		if err != nil {
 			return err
 		}
-		password = pw
-	}
-	if password == "" {
-		return fmt.Errorf("password cannot be empty")
+		pw = []byte(phrase)
+		fmt.Fprintln(os.Stderr, ui.Success.Render("🔑 Passphrase (share it separately from the link):"))
+		fmt.Fprintln(os.Stderr, ui.Link.Render(phrase))
+		fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("%d words, %.0f bits", createWords, passphrase.Bits(createWords))))
+	} else {
+		if pw, err = getPassword(password, "Password: "); err != nil {
+			return err
+		}
+		if err := checkStrength(pw); err != nil {
+			clear(pw)
+			return err
+		}
 	}
 
 	// Encrypt locally (server never sees plaintext)
-	payload, err := crypto.Encrypt(secret, password)
+	payload, err := crypto.EncryptContent(secret, pw, contentType)
+	clear(pw)
 	if err != nil {
 		return err
 	}
 
-	expiry := time.Now().Add(time.Duration(expiryMinutes) * time.Minute).Unix()
-	payload.Expiry = expiry
+	sent := time.Now()
+	expiresAt := exp.At(sent)
+	payload.Expiry = expiresAt.Unix()
 	payload.MaxViews = maxViews
 
-	url := serverURL
-	if url == "" {
-		url = os.Getenv("BURNENV_SERVER")
-	}
-	var link string
+	var link, webLink string
 	if url != "" {
-		link, err = client.Create(url, payload)
+		resp, err := client.CreateDrop(url, payload)
 		if err != nil {
-			return fmt.Errorf("server: %w", err)
+			return err
 		}
+		link, webLink = resp.Link, resp.WebLink
 	} else {
 		mock, err := store.NewMockStore("")
 		if err != nil {
@@ -120,10 +214,19 @@ func runCreate(cmd *cobra.Command, args []string) error {
 	// Output
 	if jsonOutput {
 		out := struct {
-			Link          string `json:"link"`
-			ExpiryMinutes int    `json:"expiry_minutes"`
-			MaxViews      int    `json:"max_views"`
-		}{Link: link, ExpiryMinutes: expiryMinutes, MaxViews: maxViews}
+			Link          string   `json:"link"`
+			WebLink       string   `json:"web_link,omitempty"`
+			ExpiryMinutes int      `json:"expiry_minutes"`
+			ExpiresAt     string   `json:"expires_at"`
+			MaxViews      int      `json:"max_views"`
+			ContentType   string   `json:"content_type,omitempty"`
+			Warnings      []string `json:"warnings,omitempty"`
+		}{
+			Link: link, WebLink: webLink,
+			ExpiryMinutes: int(expiresAt.Sub(sent).Round(time.Minute) / time.Minute),
+			ExpiresAt:     expiresAt.UTC().Format(time.RFC3339),
+			MaxViews:      maxViews, ContentType: contentType, Warnings: warnings,
+		}
 		enc := json.NewEncoder(os.Stdout)
 		return enc.Encode(out)
 	}
@@ -134,28 +237,144 @@ func runCreate(cmd *cobra.Command, args []string) error {
 		fmt.Fprintln(os.Stderr, ui.Success.Render("✓ Secret encrypted. Burn link (mock - local file):"))
 	}
 	fmt.Println(ui.Link.Render(link))
-	fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("Expires: %d min | Max views: %d", expiryMinutes, maxViews)))
+	if webLink != "" {
+		fmt.Fprintln(os.Stderr, ui.Muted.Render("Browser (no CLI needed): "+webLink))
+	}
+	fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("Expires %s | Max views: %d", expiry.Describe(expiresAt, time.Now()), maxViews)))
+	return nil
+}
+
+// checkCreateLimits validates --expiry and --max-views against limits.
+func checkCreateLimits(limits client.Limits, exp expiry.Spec, remote bool) error {
+	where := "for the local mock store"
+	if remote {
+		where = "on this server"
+	}
+	if err := limits.CheckExpiry(exp.Duration(time.Now())); err != nil {
+		return fmt.Errorf("%w %s", err, where)
+	}
+	if maxViews < limits.MinMaxViews || maxViews > limits.MaxMaxViews {
+		return fmt.Errorf("max-views must be between %d and %d %s", limits.MinMaxViews, limits.MaxMaxViews, where)
+	}
 	return nil
 }
 
-// readSecret reads from stdin if it's a pipe, else prompts interactively.
-func readSecret() ([]byte, error) {
+// checkPasswordOptions validates --generate-password, --words and --min-strength.
+func checkPasswordOptions() error {
+	if err := checkMinStrength(createMinStr); err != nil {
+		return err
+	}
+	if !createGenPW {
+		return nil
+	}
+	if password != "" || passwordFile != "" || passwordFD >= 0 || passwordCmd != "" {
+		return fmt.Errorf("--generate-password cannot be combined with another password source")
+	}
+	if createWords < passphrase.MinWords || createWords > passphrase.MaxWords {
+		return fmt.Errorf("words must be between %d and %d", passphrase.MinWords, passphrase.MaxWords)
+	}
+	return nil
+}
+
+// checkTUIOptions rejects flags --tui would ignore: the TUI reads a text
+// secret and its password itself.
+func checkTUIOptions() error {
+	if !useTUI {
+		return nil
+	}
+	if createFrom != "" || len(createFiles) > 0 || len(createDirs) > 0 || len(createOnly) > 0 || len(createExclude) > 0 || validateEnv {
+		return fmt.Errorf("--tui reads the secret itself; it cannot be combined with --from, --file, --dir, --only, --exclude or --validate")
+	}
+	if createGenPW || countSet(password != "", passwordFile != "", passwordFD >= 0, passwordCmd != "") > 0 {
+		return fmt.Errorf("--tui asks for the password itself; it cannot be combined with --generate-password, --password, --password-file, --password-fd or --password-cmd")
+	}
+	return nil
+}
+
+// checkMinStrength validates a --min-strength score.
+func checkMinStrength(n int) error {
+	if n < 0 || n >= len(passphrase.Labels) {
+		return fmt.Errorf("min-strength must be between 0 and %d", len(passphrase.Labels)-1)
+	}
+	return nil
+}
+
+// checkStrength shows the strength of pw on a terminal, warns when it is
+// guessable, and refuses it below --min-strength.
+func checkStrength(pw []byte) error {
+	s := passphrase.Estimate(pw)
+	if s.Score < createMinStr {
+		return withCode(apierr.WeakPassword, fmt.Errorf("password is %s (%d/4), --min-strength requires %s: %s",
+			s.Label(), s.Score, passphrase.Labels[createMinStr], s.Warning))
+	}
+	if term.IsTerminal(int(os.Stderr.Fd())) {
+		fmt.Fprintln(os.Stderr, "Strength: "+ui.StrengthMeter(s))
+	}
+	if s.Score < 3 {
+		fmt.Fprintln(os.Stderr, ui.Warning.Render(fmt.Sprintf("⚠ Password is %s (%d/4): %s. Consider --generate-password.", s.Label(), s.Score, s.Warning)))
+	}
+	return nil
+}
+
+// describeArchive lists the packed files on stderr.
+func describeArchive(data []byte) error {
+	files, err := archive.List(data)
+	if err != nil {
+		return err
+	}
+	n := 0
+	for _, f := range files {
+		if !f.Dir {
+			n++
+			fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("  %s %s (%d bytes)", f.Mode, f.Name, f.Size)))
+		}
+	}
+	fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("Packed %d file(s), %d bytes", n, len(data))))
+	return nil
+}
+
+// selectKeys reduces a .env secret to the keys allowed by --only and
+// --exclude, listing the chosen key names (never values) on stderr.
+func selectKeys(secret []byte) ([]byte, error) {
+	entries, err := dotenv.Parse(secret)
+	if err != nil {
+		return nil, withCode(apierr.BadFormat, fmt.Errorf("--only/--exclude need a valid .env: %w", err))
+	}
+	selected, err := dotenv.Select(entries, createOnly, createExclude)
+	if err != nil {
+		return nil, withCode(apierr.Usage, err)
+	}
+	if len(selected) == 0 {
+		return nil, withCode(apierr.Usage, fmt.Errorf("no keys left after --only/--exclude"))
+	}
+	keys := dotenv.Keys(selected)
+	fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("Sharing %d of %d keys: %s", len(keys), len(dotenv.Keys(entries)), strings.Join(keys, ", "))))
+	return dotenv.Render(selected, dotenv.FormatDotenv, "")
+}
+
+// lintSecret checks secret as a .env file before it is encrypted,
+// printing warnings to stderr. Syntax errors abort the create.
+func lintSecret(secret []byte) ([]string, error) {
+	warnings, err := dotenv.Lint(secret)
+	if err != nil {
+		return nil, withCode(apierr.BadFormat, fmt.Errorf("secret is not a valid .env: %w", err))
+	}
+	out := make([]string, len(warnings))
+	for i, w := range warnings {
+		out[i] = w.String()
+		fmt.Fprintln(os.Stderr, ui.Warning.Render("⚠ "+out[i]))
+	}
+	return out, nil
+}
+
+// readSecret reads the secret from stdin: verbatim when piped, line by
+// line from the prompt otherwise. At most limit+1 bytes are read, enough
+// for the caller to tell the secret is too large.
+func readSecret(limit int) ([]byte, error) {
 	stat, _ := os.Stdin.Stat()
 	if (stat.Mode() & os.ModeCharDevice) == 0 {
 		// Stdin is a pipe
-		var data []byte
-		scanner := bufio.NewScanner(os.Stdin)
-		for scanner.Scan() {
-			data = append(data, scanner.Bytes()...)
-			data = append(data, '\n')
-		}
-		if err := scanner.Err(); err != nil {
-			return nil, err
-		}
-		if len(data) > 0 && data[len(data)-1] == '\n' {
-			data = data[:len(data)-1]
-		}
-		return data, nil
+		return io.ReadAll(io.LimitReader(os.Stdin, int64(limit)+1))
 	}
 
 	// Interactive prompt
@@ -176,20 +395,20 @@ func readSecret() ([]byte, error) {
 	return []byte(strings.Join(lines, "\n")), nil
 }
 
-func promptPassword(prompt string) (string, error) {
-	fmt.Fprint(os.Stderr, prompt)
-	defer fmt.Fprintln(os.Stderr)
-	return readPassword()
+// readLimited reads up to limit+1 bytes of path (see readSecret).
+func readLimited(path string, limit int) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return io.ReadAll(io.LimitReader(f, int64(limit)+1))
 }
 
-// readPassword reads a line from stdin with terminal echo disabled.
-func readPassword() (string, error) {
-	fd := int(os.Stdin.Fd())
-	if !term.IsTerminal(fd) {
-		var s string
-		_, err := fmt.Scanln(&s)
-		return s, err
+// trimNewline removes one trailing "\n" or "\r\n".
+func trimNewline(data []byte) []byte {
+	if trimmed, ok := bytes.CutSuffix(data, []byte("\n")); ok {
+		return bytes.TrimSuffix(trimmed, []byte("\r"))
 	}
-	b, err := term.ReadPassword(fd)
-	return string(b), err
+	return data
 }
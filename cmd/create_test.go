@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+const testLimit = 64
+
+// secretInputs are read back verbatim by readSecret and readLimited; only
+// --trim (trimNewline) may change them.
+var secretInputs = []struct {
+	name    string
+	in      []byte
+	trimmed []byte
+}{
+	{"empty", nil, nil},
+	{"LF", []byte("A=1\nB=2\n"), []byte("A=1\nB=2")},
+	{"CRLF", []byte("A=1\r\nB=2\r\n"), []byte("A=1\r\nB=2")},
+	{"no newline", []byte("A=1"), []byte("A=1")},
+	{"two newlines", []byte("A=1\n\n"), []byte("A=1\n")},
+	{"lone CR", []byte("A=1\r"), []byte("A=1\r")},
+	{"CR before CRLF", []byte("A=1\r\r\n"), []byte("A=1\r")},
+	{"NUL", []byte("A=\x001\x00\n"), []byte("A=\x001\x00")},
+	{"binary", []byte{0xff, 0xfe, 0x00, 0x80, '\r', '\n', 0xc3}, []byte{0xff, 0xfe, 0x00, 0x80, '\r', '\n', 0xc3}},
+	{"at limit", bytes.Repeat([]byte{'x'}, testLimit), bytes.Repeat([]byte{'x'}, testLimit)},
+	{"at limit with LF", append(bytes.Repeat([]byte{'x'}, testLimit-1), '\n'), bytes.Repeat([]byte{'x'}, testLimit-1)},
+}
+
+func TestTrimNewline(t *testing.T) {
+	for _, tc := range secretInputs {
+		t.Run(tc.name, func(t *testing.T) {
+			if got := trimNewline(bytes.Clone(tc.in)); !bytes.Equal(got, tc.trimmed) {
+				t.Errorf("trimNewline(%q) = %q, want %q", tc.in, got, tc.trimmed)
+			}
+		})
+	}
+}
+
+func TestReadLimited(t *testing.T) {
+	for _, tc := range secretInputs {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), "secret")
+			if err := os.WriteFile(path, tc.in, 0o600); err != nil {
+				t.Fatal(err)
+			}
+			got, err := readLimited(path, testLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tc.in) {
+				t.Errorf("readLimited = %q, want %q", got, tc.in)
+			}
+		})
+	}
+}
+
+func TestReadSecretPipe(t *testing.T) {
+	for _, tc := range secretInputs {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := readSecretFrom(t, tc.in, testLimit)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !bytes.Equal(got, tc.in) {
+				t.Errorf("readSecret = %q, want %q", got, tc.in)
+			}
+		})
+	}
+}
+
+// TestReadOverLimit checks that input one byte over the limit is read as
+// limit+1 bytes, so create and send can reject it, and that nothing more
+// is buffered.
+func TestReadOverLimit(t *testing.T) {
+	for _, size := range []int{testLimit + 1, 10 * testLimit} {
+		in := bytes.Repeat([]byte{'x'}, size)
+		path := filepath.Join(t.TempDir(), "secret")
+		if err := os.WriteFile(path, in, 0o600); err != nil {
+			t.Fatal(err)
+		}
+		got, err := readLimited(path, testLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != testLimit+1 {
+			t.Errorf("readLimited of %d bytes: got %d, want %d", size, len(got), testLimit+1)
+		}
+		got, err = readSecretFrom(t, in, testLimit)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(got) != testLimit+1 {
+			t.Errorf("readSecret of %d bytes: got %d, want %d", size, len(got), testLimit+1)
+		}
+	}
+}
+
+// readSecretFrom runs readSecret with in piped to stdin.
+func readSecretFrom(t *testing.T, in []byte, limit int) ([]byte, error) {
+	t.Helper()
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer r.Close()
+	go func() {
+		w.Write(in)
+		w.Close()
+	}()
+	stdin := os.Stdin
+	os.Stdin = r
+	defer func() { os.Stdin = stdin }()
+	return readSecret(limit)
+}
+
+func TestCheckTUIOptions(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		set  func()
+		ok   bool
+	}{
+		{"tui alone", func() {}, true},
+		{"expiry and views", func() { createExpiry, maxViews, createMinStr = "1h", 3, 2 }, true},
+		{"from", func() { createFrom = ".env" }, false},
+		{"file", func() { createFiles = []string{"a.pem"} }, false},
+		{"dir", func() { createDirs = []string{"certs"} }, false},
+		{"only", func() { createOnly = []string{"DB_*"} }, false},
+		{"exclude", func() { createExclude = []string{"DEBUG"} }, false},
+		{"validate", func() { validateEnv = true }, false},
+		{"generate password", func() { createGenPW = true }, false},
+		{"password", func() { password = "hunter2" }, false},
+		{"password file", func() { passwordFile = "pw.txt" }, false},
+		{"password fd", func() { passwordFD = 3 }, false},
+		{"password cmd", func() { passwordCmd = "pass show x" }, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			resetCreateFlags(t)
+			useTUI = true
+			tc.set()
+			if err := checkTUIOptions(); (err == nil) != tc.ok {
+				t.Errorf("checkTUIOptions() = %v, want ok=%v", err, tc.ok)
+			}
+			// Without --tui the same flags are fine here
+			useTUI = false
+			if err := checkTUIOptions(); err != nil {
+				t.Errorf("without --tui: %v", err)
+			}
+		})
+	}
+}
+
+// resetCreateFlags restores the create flag variables when the test ends.
+func resetCreateFlags(t *testing.T) {
+	tui, exp, views, minStr := useTUI, createExpiry, maxViews, createMinStr
+	from, files, dirs, only, exclude, validate := createFrom, createFiles, createDirs, createOnly, createExclude, validateEnv
+	genPW, pw, pwFile, pwFD, pwCmd := createGenPW, password, passwordFile, passwordFD, passwordCmd
+	t.Cleanup(func() {
+		useTUI, createExpiry, maxViews, createMinStr = tui, exp, views, minStr
+		createFrom, createFiles, createDirs, createOnly, createExclude, validateEnv = from, files, dirs, only, exclude, validate
+		createGenPW, password, passwordFile, passwordFD, passwordCmd = genPW, pw, pwFile, pwFD, pwCmd
+	})
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"os/signal"
+	"slices"
+	"strings"
+	"syscall"
+
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/dotenv"
+	"github.com/yesahem/burnenv/internal/ui"
+)
+
+// forwardedSignals are relayed from burnenv to the child command.
+var forwardedSignals = []os.Signal{os.Interrupt, syscall.SIGTERM, syscall.SIGHUP, syscall.SIGQUIT}
+
+// exitStatus is returned when a child command exits non-zero; burnenv
+// exits with the same status and prints nothing.
+type exitStatus int
+
+func (s exitStatus) Error() string { return fmt.Sprintf("command exited with status %d", int(s)) }
+
+// commandFor resolves argv up front, so a typo fails before the drop is
+// retrieved and burned.
+func commandFor(argv []string) (*exec.Cmd, error) {
+	path, err := exec.LookPath(argv[0])
+	if err != nil {
+		return nil, withCode(apierr.Usage, err)
+	}
+	c := exec.Command(path, argv[1:]...)
+	c.Args[0] = argv[0]
+	c.Stdin, c.Stdout, c.Stderr = os.Stdin, os.Stdout, os.Stderr
+	return c, nil
+}
+
+// runWithSecrets parses plaintext as dotenv and runs c with those
+// variables added to the environment. The plaintext never touches disk
+// or stdout; the child's exit status becomes burnenv's.
+func runWithSecrets(c *exec.Cmd, plaintext []byte) error {
+	entries, err := dotenv.Parse(plaintext)
+	clear(plaintext)
+	if err != nil {
+		return withCode(apierr.BadFormat, fmt.Errorf("secret is not a valid .env: %w", err))
+	}
+	// Secret values override the inherited environment; the password does not leak
+	env := slices.DeleteFunc(os.Environ(), func(kv string) bool {
+		return strings.HasPrefix(kv, "BURNENV_PASSWORD=")
+	})
+	c.Env = append(env, dotenv.Environ(entries)...)
+	fmt.Fprintln(os.Stderr, ui.Burn.Render(fmt.Sprintf("🔥 Secret retrieved and burned. Running %s with %d variables.", c.Args[0], len(entries))))
+
+	sigs := make(chan os.Signal, 1)
+	signal.Notify(sigs, forwardedSignals...)
+	if err := c.Start(); err != nil {
+		signal.Stop(sigs)
+		return err
+	}
+	forwarding := make(chan struct{})
+	go func() {
+		defer close(forwarding)
+		for sig := range sigs {
+			c.Process.Signal(sig)
+		}
+	}()
+
+	err = c.Wait()
+	// No signal is delivered once Stop returns, so closing ends the loop
+	signal.Stop(sigs)
+	close(sigs)
+	<-forwarding
+	var ee *exec.ExitError
+	if errors.As(err, &ee) {
+		// Killed by a signal: report it the way shells do
+		if ws, ok := ee.Sys().(syscall.WaitStatus); ok && ws.Signaled() {
+			return exitStatus(128 + int(ws.Signal()))
+		}
+		return exitStatus(ee.ExitCode())
+	}
+	return err
+}
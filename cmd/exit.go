@@ -0,0 +1,113 @@
+package cmd
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/client"
+	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/store"
+	"github.com/yesahem/burnenv/internal/ui"
+)
+
+// Process exit codes, one per error code. Stable for scripts.
+var exitCodes = map[apierr.Code]int{
+	apierr.Internal:     1,
+	apierr.Usage:        2,
+	apierr.NotFound:     3,
+	apierr.Expired:      4,
+	apierr.Exhausted:    5,
+	apierr.BadPassword:  6,
+	apierr.TooLarge:     7,
+	apierr.BadRequest:   8,
+	apierr.Unauthorized: 9,
+	apierr.Forbidden:    10,
+	apierr.PoWFailed:    11,
+	apierr.RateLimited:  12,
+	apierr.BadFormat:    13,
+	apierr.Conflict:     14,
+	apierr.WeakPassword: 15,
+}
+
+// codedError attaches an error code to a CLI-side error.
+type codedError struct {
+	code apierr.Code
+	err  error
+}
+
+func (e *codedError) Error() string { return e.err.Error() }
+func (e *codedError) Unwrap() error { return e.err }
+
+// withCode marks err with code for exit status and --json output.
+func withCode(code apierr.Code, err error) error {
+	return &codedError{code: code, err: err}
+}
+
+// markUsageErrors tags positional-argument errors of c and its
+// subcommands as usage errors (flag errors are tagged by the root's
+// flag error func).
+func markUsageErrors(c *cobra.Command) {
+	if validate := c.Args; validate != nil {
+		c.Args = func(cmd *cobra.Command, args []string) error {
+			if err := validate(cmd, args); err != nil {
+				cmd.PrintErrln(cmd.UsageString())
+				return withCode(apierr.Usage, err)
+			}
+			return nil
+		}
+	}
+	for _, sub := range c.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// errorCode classifies err: explicit codes first, then server responses,
+// then known local failures (wrong password, mock store).
+func errorCode(err error) apierr.Code {
+	var ce *codedError
+	if errors.As(err, &ce) {
+		return ce.code
+	}
+	if code := client.CodeOf(err); code != "" {
+		return code
+	}
+	switch {
+	case errors.Is(err, crypto.ErrDecryptionFailed):
+		return apierr.BadPassword
+	case errors.Is(err, store.ErrSecretNotFound):
+		return apierr.NotFound
+	case errors.Is(err, store.ErrSecretExpired):
+		return apierr.Expired
+	case errors.Is(err, store.ErrSecretBurned):
+		return apierr.Exhausted
+	}
+	return apierr.Internal
+}
+
+// exit reports err (as JSON on stdout with --json) and exits with the
+// status for its code, or with a child command's own status.
+func exit(err error) {
+	// A child command's status passes through unchanged
+	var child exitStatus
+	if errors.As(err, &child) {
+		os.Exit(int(child))
+	}
+	code := errorCode(err)
+	if jsonOutput {
+		json.NewEncoder(os.Stdout).Encode(struct {
+			Error string      `json:"error"`
+			Code  apierr.Code `json:"code"`
+		}{err.Error(), code})
+	} else {
+		fmt.Fprintln(os.Stderr, ui.Error.Render(err.Error()))
+	}
+	status, ok := exitCodes[code]
+	if !ok {
+		status = exitCodes[apierr.Internal]
+	}
+	os.Exit(status)
+}
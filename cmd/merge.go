@@ -0,0 +1,126 @@
+package cmd
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/dotenv"
+	"github.com/yesahem/burnenv/internal/ui"
+	"golang.org/x/term"
+)
+
+// readMergeTarget reads and parses the --merge-into file (empty if it
+// doesn't exist). Called before the drop is fetched, so a broken target
+// fails without burning the secret.
+func readMergeTarget(path string) ([]byte, []dotenv.Entry, error) {
+	data, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, nil, nil
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	entries, err := dotenv.Parse(data)
+	if err != nil {
+		return nil, nil, withCode(apierr.BadFormat, fmt.Errorf("%s: %w", path, err))
+	}
+	return data, entries, nil
+}
+
+// mergeSecret merges the .env in plaintext into path, printing the
+// key-level diff (never values) to stderr. Differing keys follow policy,
+// or the user's answer when policy is empty.
+func mergeSecret(path string, plaintext []byte, policy string) error {
+	data, existing, err := readMergeTarget(path)
+	if err != nil {
+		return err
+	}
+	incoming, err := dotenv.Parse(plaintext)
+	clear(plaintext)
+	if err != nil {
+		return withCode(apierr.BadFormat, fmt.Errorf("secret is not a valid .env: %w", err))
+	}
+
+	changes := dotenv.Diff(existing, incoming)
+	var added, changed int
+	fmt.Fprintln(os.Stderr, ui.Prompt.Render("Merging into "+path+":"))
+	for _, c := range changes {
+		switch c.Kind {
+		case dotenv.Added:
+			added++
+			fmt.Fprintln(os.Stderr, ui.Success.Render("  + "+c.Key))
+		case dotenv.Changed:
+			changed++
+			fmt.Fprintln(os.Stderr, ui.Warning.Render("  ~ "+c.Key))
+		case dotenv.Unchanged:
+			fmt.Fprintln(os.Stderr, ui.Muted.Render("  = "+c.Key))
+		}
+	}
+
+	replace := false
+	if changed > 0 {
+		if policy == "" {
+			if policy, err = askConflict(changed); err != nil {
+				return err
+			}
+		}
+		switch policy {
+		case dotenv.ConflictFail:
+			return withCode(apierr.Conflict, fmt.Errorf("%d key(s) in %s differ from the secret; nothing written", changed, path))
+		case dotenv.ConflictReplace:
+			replace = true
+		}
+	}
+	if added == 0 && !replace {
+		fmt.Fprintln(os.Stderr, ui.Success.Render("✓ "+path+" already up to date; nothing written"))
+		return nil
+	}
+
+	merged, err := dotenv.Merge(data, incoming, replace)
+	if err != nil {
+		return err
+	}
+	backup, err := dotenv.WriteFile(path, merged)
+	if err != nil {
+		return err
+	}
+	summary := fmt.Sprintf("✓ %s: %d added", path, added)
+	if replace {
+		summary += fmt.Sprintf(", %d replaced", changed)
+	} else if changed > 0 {
+		summary += fmt.Sprintf(", %d kept", changed)
+	}
+	if backup != "" {
+		summary += " (backup: " + backup + ")"
+	}
+	fmt.Fprintln(os.Stderr, ui.Success.Render(summary))
+	return nil
+}
+
+// askConflict asks whether differing keys should be replaced.
+func askConflict(n int) (string, error) {
+	if !term.IsTerminal(int(os.Stdin.Fd())) {
+		return "", withCode(apierr.Usage, fmt.Errorf("%d key(s) differ; pass --on-conflict=%s", n, strings.Join(dotenv.ConflictPolicies, "|")))
+	}
+	reader := bufio.NewReader(os.Stdin)
+	for {
+		fmt.Fprint(os.Stderr, ui.Prompt.Render(fmt.Sprintf("%d key(s) differ. [r]eplace, [k]eep existing, or [a]bort? ", n)))
+		line, err := reader.ReadString('\n')
+		if err != nil {
+			return "", err
+		}
+		switch strings.ToLower(strings.TrimSpace(line)) {
+		case "r", "replace":
+			return dotenv.ConflictReplace, nil
+		case "k", "keep":
+			return dotenv.ConflictKeep, nil
+		case "a", "abort":
+			return dotenv.ConflictFail, nil
+		}
+	}
+}
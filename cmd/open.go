@@ -3,38 +3,142 @@ package cmd
 import (
 	"fmt"
 	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
 	"strings"
 
 	"github.com/spf13/cobra"
-	"golang.org/x/term"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/archive"
 	"github.com/yesahem/burnenv/internal/client"
 	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/dotenv"
 	"github.com/yesahem/burnenv/internal/store"
 	"github.com/yesahem/burnenv/internal/ui"
+	"golang.org/x/term"
 )
 
-var openTUI bool
+var (
+	openTUI     bool
+	openFormat  string
+	openK8sName string
+	openMerge   string
+	openOnConf  string
+	openExtract string
+)
 
 var openCmd = &cobra.Command{
-	Use:   "open [url]",
+	Use:   "open [url] [-- command [args...]]",
 	Short: "Retrieve, decrypt, and burn a secret",
 	Long: `Fetches encrypted payload, decrypts locally, prints to stdout.
-Triggers immediate destruction on the server.`,
-	Args: cobra.ExactArgs(1),
+Triggers immediate destruction on the server.
+
+With a command after --, the secret is parsed as a .env file and the
+command runs with those variables in its environment instead; nothing is
+printed. Signals are forwarded and burnenv exits with the command's status.
+
+--format parses the secret as a .env file and prints it as dotenv, json,
+yaml, export (POSIX shell), docker-env or k8s-secret (a Secret manifest).
+
+--merge-into adds the secret's keys to an existing .env, showing which
+keys are added, changed or unchanged. Changed keys follow --on-conflict, or
+you are asked. The file is replaced atomically and the old one kept as .bak.
+
+Files shared with create --file/--dir are restored under --extract-to
+(default: the current directory, or the raw tar on stdout when it is piped).
+Paths outside that directory, links and existing files are refused.`,
+	Example: `  burnenv open "$LINK" -- ./deploy.sh --prod
+  burnenv open "$LINK" --format k8s-secret --k8s-name api | kubectl apply -f -
+  eval "$(burnenv open "$LINK" --format export)"
+  burnenv open "$LINK" --merge-into .env --on-conflict replace
+  burnenv open "$LINK" --extract-to ./certs`,
+	Args: openArgs,
 	RunE: runOpen,
 }
 
 func init() {
 	rootCmd.AddCommand(openCmd)
 	openCmd.Flags().BoolVar(&openTUI, "tui", false, "Use interactive TUI mode")
+	openCmd.Flags().StringVar(&openFormat, "format", "", "Output format: "+strings.Join(dotenv.Formats, ", ")+" (default: as stored)")
+	openCmd.Flags().StringVar(&openK8sName, "k8s-name", "burnenv-secret", "metadata.name for --format k8s-secret")
+	openCmd.Flags().StringVar(&openMerge, "merge-into", "", "Merge the secret's keys into this .env file")
+	openCmd.Flags().StringVar(&openOnConf, "on-conflict", "", "For keys whose value differs with --merge-into: "+strings.Join(dotenv.ConflictPolicies, ", ")+" (default: ask)")
+	openCmd.Flags().StringVar(&openExtract, "extract-to", "", "Directory to restore shared files into")
+	addPasswordFlags(openCmd)
+}
+
+// openArgs accepts a link, optionally followed by -- and a command.
+func openArgs(cmd *cobra.Command, args []string) error {
+	dash := cmd.ArgsLenAtDash()
+	if dash == -1 {
+		return cobra.ExactArgs(1)(cmd, args)
+	}
+	if dash != 1 {
+		return fmt.Errorf("expected exactly one link before --, got %d", dash)
+	}
+	if len(args) == 1 {
+		return fmt.Errorf("missing command after --")
+	}
+	return nil
 }
 
 func runOpen(cmd *cobra.Command, args []string) error {
 	target := args[0]
 
+	// Check options and resolve the command before the secret is burned
+	if openFormat != "" && !slices.Contains(dotenv.Formats, openFormat) {
+		return withCode(apierr.Usage, fmt.Errorf("unknown format %q (use %s)", openFormat, strings.Join(dotenv.Formats, ", ")))
+	}
+	if openOnConf != "" && !slices.Contains(dotenv.ConflictPolicies, openOnConf) {
+		return withCode(apierr.Usage, fmt.Errorf("unknown --on-conflict %q (use %s)", openOnConf, strings.Join(dotenv.ConflictPolicies, ", ")))
+	}
+	if openMerge != "" {
+		if openFormat != "" {
+			return withCode(apierr.Usage, fmt.Errorf("--merge-into cannot be used with --format"))
+		}
+		_, existing, err := readMergeTarget(openMerge)
+		if err != nil {
+			return err
+		}
+		// Without a terminal there is no one to ask about conflicts
+		if len(existing) > 0 && openOnConf == "" && !term.IsTerminal(int(os.Stdin.Fd())) {
+			return withCode(apierr.Usage, fmt.Errorf("%s exists and stdin is not a terminal; pass --on-conflict=%s", openMerge, strings.Join(dotenv.ConflictPolicies, "|")))
+		}
+	} else if openOnConf != "" {
+		return withCode(apierr.Usage, fmt.Errorf("--on-conflict requires --merge-into"))
+	}
+	if openExtract != "" && (openFormat != "" || openMerge != "") {
+		return withCode(apierr.Usage, fmt.Errorf("--extract-to cannot be used with --format or --merge-into"))
+	}
+	var child *exec.Cmd
+	if len(args) > 1 {
+		if openTUI || openFormat != "" || openMerge != "" || openExtract != "" {
+			return withCode(apierr.Usage, fmt.Errorf("--tui, --format, --merge-into and --extract-to cannot be used with a command"))
+		}
+		var err error
+		if child, err = commandFor(args[1:]); err != nil {
+			return err
+		}
+	}
+	if openTUI && countSet(passwordFile != "", passwordFD >= 0, passwordCmd != "") > 0 {
+		return withCode(apierr.Usage, fmt.Errorf("--tui asks for the password itself; it cannot be used with --password-file, --password-fd or --password-cmd"))
+	}
+
+	// Password, also before the burn: a bad source mustn't cost the secret.
+	// The TUI asks for it itself.
+	var pw []byte
+	var err error
+	defer func() { clear(pw) }()
+	useTUI := openTUI && term.IsTerminal(int(os.Stdin.Fd()))
+	if !useTUI {
+		if pw, err = getPassword("", ui.Prompt.Render("Password: ")); err != nil {
+			return err
+		}
+	}
+
 	// Fetch payload: URL (server) or file path (mock)
 	var payload *crypto.EncryptedPayload
-	var err error
 	if isURL(target) {
 		payload, err = client.Get(target)
 		if err != nil {
@@ -51,48 +155,115 @@ func runOpen(cmd *cobra.Command, args []string) error {
 		}
 	}
 
-	// TUI mode for password + result display
-	fd := int(os.Stdin.Fd())
-	isTerminal := term.IsTerminal(fd)
-	if openTUI && isTerminal {
+	// TUI mode for password + result display (archives aren't displayable)
+	if useTUI && payload.ContentType == "" {
 		plaintext, err := ui.RunOpenTUI(payload)
 		if err != nil {
 			return err
 		}
+		if openMerge != "" {
+			return mergeSecret(openMerge, plaintext, openOnConf)
+		}
+		if plaintext, err = formatSecret(plaintext); err != nil {
+			return err
+		}
 		ui.PrintPlaintextToStdout(plaintext)
 		return nil
 	}
-
-	// Password
-	password := os.Getenv("BURNENV_PASSWORD")
-	if password == "" {
-		pw, err := promptPassword(ui.Prompt.Render("Password: "))
-		if err != nil {
+	if useTUI {
+		if pw, err = getPassword("", ui.Prompt.Render("Password: ")); err != nil {
 			return err
 		}
-		password = pw
-	}
-	if password == "" {
-		return fmt.Errorf("password cannot be empty")
 	}
 
 	// Decrypt locally
-	plaintext, err := crypto.Decrypt(payload, password)
+	plaintext, err := crypto.DecryptPassword(payload, pw)
+	clear(pw)
 	if err != nil {
 		return err
 	}
 
-	// Print to stdout
-	os.Stdout.Write(plaintext)
-	if len(plaintext) > 0 && plaintext[len(plaintext)-1] != '\n' {
-		os.Stdout.Write([]byte{'\n'})
+	if payload.ContentType != "" {
+		return openArchive(payload.ContentType, plaintext, child != nil)
 	}
+	if openExtract != "" {
+		fmt.Fprintln(os.Stderr, ui.Warning.Render("⚠ The secret is text, not shared files; printing it instead of extracting."))
+	}
+	if child != nil {
+		return runWithSecrets(child, plaintext)
+	}
+	if openMerge != "" {
+		fmt.Fprintln(os.Stderr, ui.Burn.Render("🔥 Secret retrieved and burned. One-time use complete."))
+		return mergeSecret(openMerge, plaintext, openOnConf)
+	}
+	if plaintext, err = formatSecret(plaintext); err != nil {
+		return err
+	}
+
+	// Print to stdout
+	ui.PrintPlaintextToStdout(plaintext)
 
 	// Destruction notice (to stderr so it doesn't pollute piped output)
 	fmt.Fprintln(os.Stderr, ui.Burn.Render("🔥 Secret retrieved and burned. One-time use complete."))
 	return nil
 }
 
+// openArchive restores a file share (create --file/--dir) under
+// --extract-to. Without it, the tar goes to stdout when piped and is
+// extracted into the current directory otherwise.
+func openArchive(contentType string, data []byte, hasCommand bool) error {
+	burned := ui.Burn.Render("🔥 Secret retrieved and burned. One-time use complete.")
+	if contentType != crypto.ContentTypeTar {
+		// Written as-is: a newer sender may know formats this version doesn't
+		os.Stdout.Write(data)
+		fmt.Fprintln(os.Stderr, burned)
+		return nil
+	}
+	if hasCommand || openFormat != "" || openMerge != "" {
+		// Already burned; keep the files rather than lose them
+		fmt.Fprintln(os.Stderr, ui.Warning.Render("⚠ The secret is shared files, not a .env; extracting them instead."))
+	}
+	dir := openExtract
+	if dir == "" {
+		if !term.IsTerminal(int(os.Stdout.Fd())) {
+			os.Stdout.Write(data)
+			fmt.Fprintln(os.Stderr, burned)
+			return nil
+		}
+		dir = "."
+	}
+	files, err := archive.Extract(data, dir)
+	n := 0
+	for _, f := range files {
+		if !f.Dir {
+			n++
+			fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("  %s %s (%d bytes)", f.Mode, filepath.Join(dir, filepath.FromSlash(f.Name)), f.Size)))
+		}
+	}
+	fmt.Fprintln(os.Stderr, burned)
+	if err != nil {
+		return withCode(apierr.BadFormat, fmt.Errorf("extract: %w", err))
+	}
+	fmt.Fprintln(os.Stderr, ui.Success.Render(fmt.Sprintf("✓ Extracted %d file(s) to %s", n, dir)))
+	return nil
+}
+
+// formatSecret renders plaintext in --format, or returns it unchanged.
+func formatSecret(plaintext []byte) ([]byte, error) {
+	if openFormat == "" {
+		return plaintext, nil
+	}
+	entries, err := dotenv.Parse(plaintext)
+	clear(plaintext)
+	if err == nil {
+		var out []byte
+		if out, err = dotenv.Render(entries, openFormat, openK8sName); err == nil {
+			return out, nil
+		}
+	}
+	return nil, withCode(apierr.BadFormat, fmt.Errorf("cannot render secret as %s: %w", openFormat, err))
+}
+
 func isURL(s string) bool {
 	return strings.HasPrefix(s, "http://") || strings.HasPrefix(s, "https://")
 }
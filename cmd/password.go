@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"golang.org/x/term"
+)
+
+// Password sources shared by create and open. Unlike --password and
+// BURNENV_PASSWORD, they don't expose the password in shell history or
+// /proc/<pid>/environ.
+var (
+	passwordFile string
+	passwordFD   int
+	passwordCmd  string
+)
+
+// maxPasswordBytes caps what is read from a password source.
+const maxPasswordBytes = 4096
+
+func addPasswordFlags(c *cobra.Command) {
+	c.Flags().StringVar(&passwordFile, "password-file", "", "Read the password from the first line of this file")
+	c.Flags().IntVar(&passwordFD, "password-fd", -1, "Read the password from the first line of this file descriptor (e.g. 3 with 3<file)")
+	c.Flags().StringVar(&passwordCmd, "password-cmd", "", `Run this shell command and use the first line of its output as the password (e.g. "pass show team/burnenv")`)
+}
+
+// getPassword returns the password from, in order: explicit (--password),
+// --password-file, --password-fd or --password-cmd (at most one of these),
+// BURNENV_PASSWORD, or a prompt. The caller should clear the result once
+// done with it.
+func getPassword(explicit, prompt string) ([]byte, error) {
+	var pw []byte
+	var err error
+	switch n := countSet(explicit != "", passwordFile != "", passwordFD >= 0, passwordCmd != ""); {
+	case n > 1:
+		return nil, withCode(apierr.Usage, errors.New("use only one of --password, --password-file, --password-fd and --password-cmd"))
+	case explicit != "":
+		pw = []byte(explicit)
+	case passwordFile != "":
+		pw, err = readPasswordFile(passwordFile)
+	case passwordFD >= 0:
+		pw, err = readPasswordFD(passwordFD)
+	case passwordCmd != "":
+		pw, err = runPasswordCmd(passwordCmd)
+	case os.Getenv("BURNENV_PASSWORD") != "":
+		pw = []byte(os.Getenv("BURNENV_PASSWORD"))
+	default:
+		pw, err = promptPassword(prompt)
+	}
+	if err != nil {
+		clear(pw)
+		return nil, err
+	}
+	if len(pw) == 0 {
+		return nil, fmt.Errorf("password cannot be empty")
+	}
+	return pw, nil
+}
+
+func countSet(flags ...bool) int {
+	n := 0
+	for _, f := range flags {
+		if f {
+			n++
+		}
+	}
+	return n
+}
+
+func readPasswordFile(path string) ([]byte, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, withCode(apierr.Usage, fmt.Errorf("--password-file: %w", err))
+	}
+	defer f.Close()
+	return firstLine(f, "--password-file")
+}
+
+// readPasswordFD reads from an inherited descriptor and closes it, except
+// stdin, stdout and stderr, which the rest of the command still uses.
+func readPasswordFD(fd int) ([]byte, error) {
+	f := os.NewFile(uintptr(fd), fmt.Sprintf("fd %d", fd))
+	if f == nil {
+		return nil, withCode(apierr.Usage, fmt.Errorf("--password-fd: invalid descriptor %d", fd))
+	}
+	if fd > 2 {
+		defer f.Close()
+	}
+	return firstLine(f, "--password-fd")
+}
+
+// runPasswordCmd runs command with the user's shell conventions. Its
+// stderr and stdin stay attached so helpers like pass or gpg can prompt.
+func runPasswordCmd(command string) ([]byte, error) {
+	c := exec.Command("sh", "-c", command)
+	if runtime.GOOS == "windows" {
+		c = exec.Command("cmd", "/C", command)
+	}
+	c.Stdin = os.Stdin
+	c.Stderr = os.Stderr
+	out, err := c.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := c.Start(); err != nil {
+		return nil, withCode(apierr.Usage, fmt.Errorf("--password-cmd: %w", err))
+	}
+	pw, readErr := firstLine(out, "--password-cmd")
+	io.Copy(io.Discard, out)
+	if err := c.Wait(); err != nil {
+		clear(pw)
+		return nil, withCode(apierr.Usage, fmt.Errorf("--password-cmd: %w", err))
+	}
+	return pw, readErr
+}
+
+// firstLine reads r into a fixed buffer and returns a copy of its first
+// line without the line ending, wiping everything it read.
+func firstLine(r io.Reader, source string) ([]byte, error) {
+	buf := make([]byte, maxPasswordBytes+1)
+	defer clear(buf)
+	n, err := io.ReadFull(r, buf)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, withCode(apierr.Usage, fmt.Errorf("%s: %w", source, err))
+	}
+	buf = buf[:n]
+	line, _, _ := bytes.Cut(buf, []byte("\n"))
+	line = bytes.TrimSuffix(line, []byte("\r"))
+	if len(line) > maxPasswordBytes {
+		return nil, withCode(apierr.Usage, fmt.Errorf("%s: password is longer than %d bytes", source, maxPasswordBytes))
+	}
+	return bytes.Clone(line), nil
+}
+
+func promptPassword(prompt string) ([]byte, error) {
+	fmt.Fprint(os.Stderr, prompt)
+	defer fmt.Fprintln(os.Stderr)
+	return readPassword()
+}
+
+// readPassword reads a line from stdin with terminal echo disabled. When
+// stdin is not a terminal the whole line is used, spaces included, and
+// nothing past it is consumed.
+func readPassword() ([]byte, error) {
+	fd := int(os.Stdin.Fd())
+	if term.IsTerminal(fd) {
+		return term.ReadPassword(fd)
+	}
+	line := make([]byte, 0, maxPasswordBytes+1) // Never reallocated, so one clear wipes it
+	b := make([]byte, 1)
+	defer clear(b)
+	for len(line) <= maxPasswordBytes {
+		n, err := os.Stdin.Read(b)
+		if n == 1 && b[0] != '\n' {
+			line = append(line, b[0])
+			continue
+		}
+		if n == 1 || errors.Is(err, io.EOF) {
+			return bytes.TrimSuffix(line, []byte("\r")), nil
+		}
+		if err != nil {
+			clear(line)
+			return nil, err
+		}
+	}
+	clear(line)
+	return nil, withCode(apierr.Usage, fmt.Errorf("password is longer than %d bytes", maxPasswordBytes))
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+// TestPasswordFDKeepsStdio checks --password-fd 0 doesn't close stdin for
+// the rest of the command. It reruns itself with stdin from a file.
+func TestPasswordFDKeepsStdio(t *testing.T) {
+	if os.Getenv("BURNENV_TEST_PASSWORD_FD") == "1" {
+		pw, err := readPasswordFD(0)
+		if err != nil || string(pw) != "hunter2" {
+			t.Fatalf("password %q, %v", pw, err)
+		}
+		if _, err := os.Stdin.Stat(); err != nil {
+			t.Fatalf("stdin closed by --password-fd 0: %v", err)
+		}
+		return
+	}
+	path := filepath.Join(t.TempDir(), "pw")
+	if err := os.WriteFile(path, []byte("hunter2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	c := exec.Command(os.Args[0], "-test.run=^TestPasswordFDKeepsStdio$")
+	c.Env = append(os.Environ(), "BURNENV_TEST_PASSWORD_FD=1")
+	c.Stdin = f
+	if out, err := c.CombinedOutput(); err != nil {
+		t.Errorf("%v\n%s", err, out)
+	}
+}
+
+func TestPasswordFD(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "pw")
+	if err := os.WriteFile(path, []byte("hunter2\r\nrest\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	pw, err := readPasswordFD(int(f.Fd()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(pw) != "hunter2" {
+		t.Errorf("password %q, want hunter2", pw)
+	}
+}
@@ -0,0 +1,92 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/client"
+	"github.com/yesahem/burnenv/internal/codes"
+	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/pake"
+	"github.com/yesahem/burnenv/internal/server"
+	"github.com/yesahem/burnenv/internal/ui"
+)
+
+var (
+	receiveServerURL string
+	receiveTimeout   time.Duration
+)
+
+func init() {
+	rootCmd.AddCommand(receiveCmd)
+	receiveCmd.Flags().StringVar(&receiveServerURL, "server", "", "Server base URL (default: BURNENV_SERVER)")
+	receiveCmd.Flags().DurationVar(&receiveTimeout, "timeout", 2*time.Minute, "How long to wait for the sender")
+}
+
+var receiveCmd = &cobra.Command{
+	Use:   "receive [code]",
+	Short: "Receive a secret sent with burnenv send",
+	Long: `Exchanges keys with the sender using the short code, decrypts locally,
+prints to stdout and burns the code.`,
+	Args: cobra.ExactArgs(1),
+	RunE: runReceive,
+}
+
+func runReceive(cmd *cobra.Command, args []string) error {
+	url := receiveServerURL
+	if url == "" {
+		url = os.Getenv("BURNENV_SERVER")
+	}
+	if url == "" {
+		return fmt.Errorf("receive requires a server (use --server or BURNENV_SERVER)")
+	}
+	channel, words, err := codes.Parse(args[0])
+	if err != nil {
+		return err
+	}
+
+	idA, idB := codeIdentities(channel)
+	state, msg, err := pake.Start(pake.RoleB, []byte(words), idA, idB)
+	if err != nil {
+		return err
+	}
+	attempt, peer, token, err := client.ClaimCode(url, channel, msg)
+	if err != nil {
+		return err
+	}
+	key, err := state.Finish(peer)
+	if err != nil {
+		return err
+	}
+
+	deadline := time.Now().Add(receiveTimeout)
+	var sealed *crypto.SealedPayload
+	for sealed == nil {
+		if time.Now().After(deadline) {
+			return fmt.Errorf("timed out waiting for the sender")
+		}
+		sealed, err = client.FetchSealed(url, channel, attempt)
+		if err != nil {
+			return err
+		}
+		if sealed == nil {
+			time.Sleep(codePollInterval)
+		}
+	}
+
+	plaintext, err := crypto.OpenWithKey(sealed, key)
+	if err != nil {
+		left := server.MaxCodeAttempts - attempt - 1
+		return withCode(apierr.BadPassword, fmt.Errorf("wrong code: could not decrypt (%d attempt(s) left before the code burns)", left))
+	}
+	if err := client.CloseCode(url, channel, attempt, token, deliveryProof(key)); err != nil {
+		fmt.Fprintln(os.Stderr, ui.Muted.Render("warning: could not confirm delivery: "+err.Error()))
+	}
+
+	ui.PrintPlaintextToStdout(plaintext)
+	fmt.Fprintln(os.Stderr, ui.Burn.Render("🔥 Secret received and code burned. One-time use complete."))
+	return nil
+}
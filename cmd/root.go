@@ -1,16 +1,21 @@
 package cmd
 
 import (
-	"fmt"
-	"os"
+	"strings"
 
 	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/passphrase"
 	"github.com/yesahem/burnenv/internal/ui"
+	"github.com/yesahem/burnenv/internal/version"
 )
 
 var (
 	// jsonOutput outputs responses as JSON for scripting
 	jsonOutput bool
+
+	// rootMinStr is --min-strength for the TUI's secure flow
+	rootMinStr int
 )
 
 var rootCmd = &cobra.Command{
@@ -23,7 +28,8 @@ Secrets are encrypted locally, stored only as ciphertext, and
 destroyed after delivery or expiry. The server never sees plaintext.
 
 Run without arguments to launch the interactive TUI.`,
-	RunE: runRoot,
+	Version: version.Version,
+	RunE:    runRoot,
 }
 
 func runRoot(cmd *cobra.Command, args []string) error {
@@ -31,17 +37,28 @@ func runRoot(cmd *cobra.Command, args []string) error {
 		// With --json, show help instead of TUI
 		return cmd.Help()
 	}
-	return ui.RunMainTUI()
+	if err := checkMinStrength(rootMinStr); err != nil {
+		return withCode(apierr.Usage, err)
+	}
+	return ui.RunMainTUI(rootMinStr)
 }
 
 func init() {
+	// Errors are printed once by Execute; usage only for usage errors
+	rootCmd.SilenceErrors = true
+	rootCmd.SilenceUsage = true
+	rootCmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+		cmd.PrintErrln(cmd.UsageString())
+		return withCode(apierr.Usage, err)
+	})
 	rootCmd.PersistentFlags().BoolVar(&jsonOutput, "json", false, "Output responses as JSON (for scripting)")
+	rootCmd.Flags().IntVar(&rootMinStr, "min-strength", 0, "Refuse passwords scoring below this in the TUI (0-4: "+strings.Join(passphrase.Labels, ", ")+")")
 }
 
-// Execute runs the root command
+// Execute runs the root command and exits with the error's exit code
 func Execute() {
+	markUsageErrors(rootCmd)
 	if err := rootCmd.Execute(); err != nil {
-		fmt.Fprintln(os.Stderr, ui.Error.Render(err.Error()))
-		os.Exit(1)
+		exit(err)
 	}
 }
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/client"
+	"github.com/yesahem/burnenv/internal/codes"
+	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/pake"
+	"github.com/yesahem/burnenv/internal/ui"
+)
+
+var (
+	sendExpiryMinutes int
+	sendWords         int
+	sendServerURL     string
+)
+
+// codePollInterval is how often send/receive poll the server for progress.
+const codePollInterval = time.Second
+
+func init() {
+	rootCmd.AddCommand(sendCmd)
+	sendCmd.Flags().IntVar(&sendExpiryMinutes, "expiry", 10, "Minutes the code stays valid")
+	sendCmd.Flags().IntVar(&sendWords, "words", codes.DefaultWords, "Number of words in the code")
+	sendCmd.Flags().StringVar(&sendServerURL, "server", "", "Server base URL (default: BURNENV_SERVER)")
+}
+
+var sendCmd = &cobra.Command{
+	Use:   "send",
+	Short: "Send a secret using a short spoken code",
+	Long: `Reads secret from STDIN or interactive prompt and prints a short code
+such as 7-crossover-clockwork. The recipient runs "burnenv receive <code>".
+
+The code words never leave this machine: both sides run SPAKE2 through the
+server, so a wrong guess costs one of a few attempts and offline guessing
+is impossible. Stay online until the recipient has received the secret.`,
+	RunE: runSend,
+}
+
+// codeIdentities binds the SPAKE2 transcript to the channel.
+func codeIdentities(channel int) ([]byte, []byte) {
+	return []byte(fmt.Sprintf("burnenv-send:%d", channel)), []byte(fmt.Sprintf("burnenv-receive:%d", channel))
+}
+
+// deliveryProof is what a receiver sends when confirming delivery. Only a
+// receiver that agreed the session key with the sender can compute it.
+func deliveryProof(key []byte) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte("burnenv-delivered"))
+	return mac.Sum(nil)
+}
+
+func runSend(cmd *cobra.Command, args []string) error {
+	url := sendServerURL
+	if url == "" {
+		url = os.Getenv("BURNENV_SERVER")
+	}
+	if url == "" {
+		return fmt.Errorf("send requires a server (use --server or BURNENV_SERVER)")
+	}
+
+	maxSize := client.LimitsFor(url).MaxSecretBytes()
+	secret, err := readSecret(maxSize)
+	if err != nil {
+		return err
+	}
+	if len(secret) > maxSize {
+		return withCode(apierr.TooLarge, fmt.Errorf("secret is larger than the %d byte limit", maxSize))
+	}
+	if len(secret) == 0 {
+		return fmt.Errorf("secret cannot be empty")
+	}
+
+	words, err := codes.RandomWords(sendWords)
+	if err != nil {
+		return err
+	}
+	// The sender's SPAKE2 message depends only on the words, so it can be
+	// registered before the channel number is known.
+	state, msg, err := pake.Start(pake.RoleA, []byte(words), nil, nil)
+	if err != nil {
+		return err
+	}
+	expiry := time.Now().Add(time.Duration(sendExpiryMinutes) * time.Minute)
+	channel, token, err := client.OpenCode(url, msg, expiry.Unix())
+	if err != nil {
+		return fmt.Errorf("server: %w", err)
+	}
+	state.SetIdentities(codeIdentities(channel))
+	code := codes.Format(channel, words)
+
+	if jsonOutput {
+		out := struct {
+			Code          string `json:"code"`
+			ExpiryMinutes int    `json:"expiry_minutes"`
+		}{Code: code, ExpiryMinutes: sendExpiryMinutes}
+		if err := json.NewEncoder(os.Stdout).Encode(out); err != nil {
+			return err
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, ui.Success.Render("✓ Secret ready. Tell the recipient to run:"))
+		fmt.Println(ui.Link.Render("burnenv receive " + code))
+		fmt.Fprintln(os.Stderr, ui.Muted.Render(fmt.Sprintf("Waiting for recipient... (expires in %d min, Ctrl+C to cancel)", sendExpiryMinutes)))
+	}
+
+	var keys [][]byte // Session key per receiver attempt; nil if it failed
+	for time.Now().Before(expiry) {
+		progress, err := client.CodeStatus(url, channel, token)
+		if err != nil {
+			return err
+		}
+		if progress.Closed {
+			// Only the receiver holding the session key can prove delivery
+			if progress.ClosedBy >= len(keys) || keys[progress.ClosedBy] == nil ||
+				!hmac.Equal(progress.Proof, deliveryProof(keys[progress.ClosedBy])) {
+				return fmt.Errorf("code was closed by a receiver without the right code; the secret was not delivered")
+			}
+			fmt.Fprintln(os.Stderr, ui.Burn.Render("🔥 Secret delivered and burned. Code is no longer valid."))
+			return nil
+		}
+		// Seal the secret once per receiver attempt. Only a receiver who
+		// used the right code derives the same key and can open it.
+		for len(keys) < len(progress.Attempts) {
+			attempt := len(keys)
+			key, err := state.Finish(progress.Attempts[attempt])
+			keys = append(keys, key)
+			if err != nil {
+				continue
+			}
+			payload, err := crypto.SealWithKey(secret, key)
+			if err != nil {
+				return err
+			}
+			if err := client.SealCode(url, channel, attempt, token, payload); err != nil {
+				return err
+			}
+		}
+		time.Sleep(codePollInterval)
+	}
+	return fmt.Errorf("code expired before the secret was received")
+}
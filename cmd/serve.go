@@ -1,58 +1,180 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"os"
 	"os/signal"
 	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
+	"github.com/yesahem/burnenv/internal/audit"
 	"github.com/yesahem/burnenv/internal/server"
 	"github.com/yesahem/burnenv/internal/ui"
+	"github.com/yesahem/burnenv/internal/web"
 )
 
 var (
-	serveAddr   string
-	serveBaseURL string
+	// serveFlags receives flag values; runServe merges them over the
+	// config file and environment via server.LoadConfig.
+	serveFlags      = server.DefaultConfig()
+	serveConfigFile string
 )
 
 func init() {
 	rootCmd.AddCommand(serveCmd)
-	serveCmd.Flags().StringVarP(&serveAddr, "addr", "a", ":8080", "Listen address")
-	serveCmd.Flags().StringVar(&serveBaseURL, "base-url", "http://localhost:8080", "Base URL for generated links")
+	serveCmd.Flags().StringVar(&serveConfigFile, "config", "", "YAML or TOML config file (env: BURNENV_CONFIG)")
+	serveFlags.BindFlags(serveCmd.Flags())
 }
 
 var serveCmd = &cobra.Command{
 	Use:   "serve",
 	Short: "Run the BurnEnv backend server",
 	Long: `Starts the HTTP API for storing encrypted blobs.
-In-memory only: restart loses all data. No persistence.`,
+In-memory only: restart loses all data. No persistence.
+
+Every setting can also come from a config file (--config, keys in
+snake_case, e.g. max_expiry: 1h) or a BURNENV_* environment variable
+(e.g. BURNENV_MAX_EXPIRY=1h). Flags override env, env overrides the file.`,
 	RunE: runServe,
 }
 
 func runServe(cmd *cobra.Command, args []string) error {
+	configFile := serveConfigFile
+	if configFile == "" {
+		configFile = os.Getenv("BURNENV_CONFIG")
+	}
+	cfg, err := server.LoadConfig(configFile, os.LookupEnv, cmd.Flags())
+	if err != nil {
+		return err
+	}
+
+	logger, err := server.NewLogger(os.Stderr, cfg.LogFormat, cfg.LogLevel)
+	if err != nil {
+		return err
+	}
+
 	store := server.NewStore()
 	defer store.Stop()
 
-	handler := server.Handler(store, serveBaseURL)
+	var challenges *server.PoW
+	if cfg.PoWEnabled {
+		challenges, err = server.NewPoW(store, cfg.PoW)
+		if err != nil {
+			return err
+		}
+	}
+
+	var keys *server.KeyRing
+	if cfg.APIKeysFile != "" {
+		keys, err = server.LoadKeyRing(cfg.APIKeysFile)
+		if err != nil {
+			return err
+		}
+	}
+
+	if cfg.AuditLog != "" {
+		auditLog, err := audit.Open(cfg.AuditLog)
+		if err != nil {
+			return err
+		}
+		auditWriter := server.NewAuditWriter(auditLog, logger)
+		store.AddObserver(auditWriter.Observe)
+		defer func() {
+			// Stop the cleanup loop first so no expiry event races the close
+			store.Stop()
+			auditWriter.Close()
+			auditLog.Close()
+		}()
+	}
+
+	health := &server.Health{}
+	metrics := server.NewMetrics(store)
+	handler := server.AccessLog(logger, metrics.Middleware(server.Security(cfg, health.Routes(web.Routes(server.Handler(store, cfg, challenges, keys))))))
+	// Cleartext HTTP/2 (h2c) lets gRPC clients reach the RPC API without TLS
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
 	srv := &http.Server{
-		Addr:    serveAddr,
-		Handler: handler,
+		Handler:   handler,
+		Protocols: &protocols,
+	}
+
+	// Sockets come from systemd when socket-activated, else from the config
+	listeners, err := server.Listen(cfg)
+	if err != nil {
+		return err
 	}
 
-	// Graceful shutdown
+	// Admin listener: kept off the public address so metrics are not exposed
+	var admin *http.Server
+	if listeners.Admin != nil {
+		adminMux := http.NewServeMux()
+		adminMux.Handle("GET /metrics", metrics.Handler())
+		admin = &http.Server{Handler: health.Routes(adminMux)}
+		go func() {
+			if err := admin.Serve(listeners.Admin); err != http.ErrServerClosed {
+				fmt.Fprintln(os.Stderr, ui.Error.Render("admin listener: "+err.Error()))
+			}
+		}()
+	}
+
+	// Graceful shutdown: fail readiness, wait for the load balancer to
+	// notice, then let in-flight requests finish within the grace period.
+	// A second signal cuts the drain short and closes every connection.
+	drained := make(chan struct{})
 	go func() {
+		defer close(drained)
 		sig := make(chan os.Signal, 1)
 		signal.Notify(sig, syscall.SIGINT, syscall.SIGTERM)
 		<-sig
-		srv.Close()
+		health.SetDraining()
+		fmt.Fprintln(os.Stderr, ui.Muted.Render("Draining... (signal again to stop now)"))
+
+		force, stop := context.WithCancel(context.Background())
+		defer stop()
+		go func() {
+			select {
+			case <-sig:
+				fmt.Fprintln(os.Stderr, ui.Error.Render("Second signal: closing all connections"))
+				stop()
+			case <-force.Done():
+			}
+		}()
+		select {
+		case <-time.After(cfg.DrainDelay):
+		case <-force.Done():
+		}
+
+		ctx, cancel := context.WithTimeout(force, cfg.ShutdownGrace)
+		defer cancel()
+		if err := srv.Shutdown(ctx); err != nil {
+			if force.Err() == nil {
+				fmt.Fprintln(os.Stderr, ui.Error.Render("shutdown grace period exceeded; closing remaining connections"))
+			}
+			srv.Close()
+		}
+		if admin != nil {
+			// Its own grace period: srv may have used up ctx
+			actx, acancel := context.WithTimeout(force, cfg.ShutdownGrace)
+			defer acancel()
+			if err := admin.Shutdown(actx); err != nil {
+				admin.Close()
+			}
+		}
 	}()
 
-	fmt.Fprintln(os.Stderr, ui.Success.Render("BurnEnv server listening on "+serveAddr))
-	fmt.Fprintln(os.Stderr, ui.Muted.Render("Base URL: "+serveBaseURL))
-	if err := srv.ListenAndServe(); err != http.ErrServerClosed {
+	fmt.Fprintln(os.Stderr, ui.Success.Render("BurnEnv server listening on "+server.Describe(listeners.Public)))
+	fmt.Fprintln(os.Stderr, ui.Muted.Render("Base URL: "+cfg.BaseURL))
+	if admin != nil {
+		fmt.Fprintln(os.Stderr, ui.Muted.Render("Metrics: "+server.Describe(listeners.Admin)+" /metrics"))
+	}
+	if err := srv.Serve(listeners.Public); err != http.ErrServerClosed {
 		return err
 	}
+	<-drained
+	// Deferred store.Stop() now halts the cleanup loop
 	return nil
 }
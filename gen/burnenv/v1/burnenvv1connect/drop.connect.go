@@ -0,0 +1,276 @@
+// BurnEnv RPC API, served by `burnenv serve` next to the JSON API on the
+// same port (Connect, gRPC and gRPC-Web protocols). It shares the JSON API's
+// store, validation, API keys and proof-of-work, so drops created over one
+// can be retrieved over the other.
+//
+// Regenerate with: buf generate (see buf.gen.yaml)
+
+// Code generated by protoc-gen-connect-go. DO NOT EDIT.
+//
+// Source: burnenv/v1/drop.proto
+
+package burnenvv1connect
+
+import (
+	connect "connectrpc.com/connect"
+	context "context"
+	errors "errors"
+	v1 "github.com/yesahem/burnenv/gen/burnenv/v1"
+	http "net/http"
+	strings "strings"
+)
+
+// This is a compile-time assertion to ensure that this generated file and the connect package are
+// compatible. If you get a compiler error that this constant is not defined, this code was
+// generated with a version of connect newer than the one compiled into your binary. You can fix the
+// problem by either regenerating this code with an older version of connect or updating the connect
+// version compiled into your binary.
+const _ = connect.IsAtLeastVersion1_13_0
+
+const (
+	// DropServiceName is the fully-qualified name of the DropService service.
+	DropServiceName = "burnenv.v1.DropService"
+)
+
+// These constants are the fully-qualified names of the RPCs defined in this package. They're
+// exposed at runtime as Spec.Procedure and as the final two segments of the HTTP route.
+//
+// Note that these are different from the fully-qualified method names used by
+// google.golang.org/protobuf/reflect/protoreflect. To convert from these constants to
+// reflection-formatted method names, remove the leading slash and convert the remaining slash to a
+// period.
+const (
+	// DropServiceGetInfoProcedure is the fully-qualified name of the DropService's GetInfo RPC.
+	DropServiceGetInfoProcedure = "/burnenv.v1.DropService/GetInfo"
+	// DropServiceCreateDropProcedure is the fully-qualified name of the DropService's CreateDrop RPC.
+	DropServiceCreateDropProcedure = "/burnenv.v1.DropService/CreateDrop"
+	// DropServiceGetDropProcedure is the fully-qualified name of the DropService's GetDrop RPC.
+	DropServiceGetDropProcedure = "/burnenv.v1.DropService/GetDrop"
+	// DropServiceGetDropStatusProcedure is the fully-qualified name of the DropService's GetDropStatus
+	// RPC.
+	DropServiceGetDropStatusProcedure = "/burnenv.v1.DropService/GetDropStatus"
+	// DropServiceRevokeDropProcedure is the fully-qualified name of the DropService's RevokeDrop RPC.
+	DropServiceRevokeDropProcedure = "/burnenv.v1.DropService/RevokeDrop"
+	// DropServiceWatchDropProcedure is the fully-qualified name of the DropService's WatchDrop RPC.
+	DropServiceWatchDropProcedure = "/burnenv.v1.DropService/WatchDrop"
+)
+
+// DropServiceClient is a client for the burnenv.v1.DropService service.
+type DropServiceClient interface {
+	// Server version, limits and features (mirrors GET /v1/info).
+	GetInfo(context.Context, *connect.Request[v1.GetInfoRequest]) (*connect.Response[v1.GetInfoResponse], error)
+	// Store an encrypted payload. Send "Authorization: Bearer <key>" and the
+	// X-BurnEnv-Challenge / X-BurnEnv-Nonce headers when the server requires them.
+	CreateDrop(context.Context, *connect.Request[v1.CreateDropRequest]) (*connect.Response[v1.CreateDropResponse], error)
+	// Retrieve the payload and consume one view (burned after the last).
+	GetDrop(context.Context, *connect.Request[v1.GetDropRequest]) (*connect.Response[v1.GetDropResponse], error)
+	// Remaining views and expiry of a drop. Never consumes a view.
+	GetDropStatus(context.Context, *connect.Request[v1.GetDropStatusRequest]) (*connect.Response[v1.GetDropStatusResponse], error)
+	// Burn a drop without retrieving it.
+	RevokeDrop(context.Context, *connect.Request[v1.RevokeDropRequest]) (*connect.Response[v1.RevokeDropResponse], error)
+	// Stream a drop's lifecycle events until it is burned, expired or revoked.
+	// Never consumes a view.
+	WatchDrop(context.Context, *connect.Request[v1.WatchDropRequest]) (*connect.ServerStreamForClient[v1.DropEvent], error)
+}
+
+// NewDropServiceClient constructs a client for the burnenv.v1.DropService service. By default, it
+// uses the Connect protocol with the binary Protobuf Codec, asks for gzipped responses, and sends
+// uncompressed requests. To use the gRPC or gRPC-Web protocols, supply the connect.WithGRPC() or
+// connect.WithGRPCWeb() options.
+//
+// The URL supplied here should be the base URL for the Connect or gRPC server (for example,
+// http://api.acme.com or https://acme.com/grpc).
+func NewDropServiceClient(httpClient connect.HTTPClient, baseURL string, opts ...connect.ClientOption) DropServiceClient {
+	baseURL = strings.TrimRight(baseURL, "/")
+	dropServiceMethods := v1.File_burnenv_v1_drop_proto.Services().ByName("DropService").Methods()
+	return &dropServiceClient{
+		getInfo: connect.NewClient[v1.GetInfoRequest, v1.GetInfoResponse](
+			httpClient,
+			baseURL+DropServiceGetInfoProcedure,
+			connect.WithSchema(dropServiceMethods.ByName("GetInfo")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		createDrop: connect.NewClient[v1.CreateDropRequest, v1.CreateDropResponse](
+			httpClient,
+			baseURL+DropServiceCreateDropProcedure,
+			connect.WithSchema(dropServiceMethods.ByName("CreateDrop")),
+			connect.WithClientOptions(opts...),
+		),
+		getDrop: connect.NewClient[v1.GetDropRequest, v1.GetDropResponse](
+			httpClient,
+			baseURL+DropServiceGetDropProcedure,
+			connect.WithSchema(dropServiceMethods.ByName("GetDrop")),
+			connect.WithClientOptions(opts...),
+		),
+		getDropStatus: connect.NewClient[v1.GetDropStatusRequest, v1.GetDropStatusResponse](
+			httpClient,
+			baseURL+DropServiceGetDropStatusProcedure,
+			connect.WithSchema(dropServiceMethods.ByName("GetDropStatus")),
+			connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+			connect.WithClientOptions(opts...),
+		),
+		revokeDrop: connect.NewClient[v1.RevokeDropRequest, v1.RevokeDropResponse](
+			httpClient,
+			baseURL+DropServiceRevokeDropProcedure,
+			connect.WithSchema(dropServiceMethods.ByName("RevokeDrop")),
+			connect.WithClientOptions(opts...),
+		),
+		watchDrop: connect.NewClient[v1.WatchDropRequest, v1.DropEvent](
+			httpClient,
+			baseURL+DropServiceWatchDropProcedure,
+			connect.WithSchema(dropServiceMethods.ByName("WatchDrop")),
+			connect.WithClientOptions(opts...),
+		),
+	}
+}
+
+// dropServiceClient implements DropServiceClient.
+type dropServiceClient struct {
+	getInfo       *connect.Client[v1.GetInfoRequest, v1.GetInfoResponse]
+	createDrop    *connect.Client[v1.CreateDropRequest, v1.CreateDropResponse]
+	getDrop       *connect.Client[v1.GetDropRequest, v1.GetDropResponse]
+	getDropStatus *connect.Client[v1.GetDropStatusRequest, v1.GetDropStatusResponse]
+	revokeDrop    *connect.Client[v1.RevokeDropRequest, v1.RevokeDropResponse]
+	watchDrop     *connect.Client[v1.WatchDropRequest, v1.DropEvent]
+}
+
+// GetInfo calls burnenv.v1.DropService.GetInfo.
+func (c *dropServiceClient) GetInfo(ctx context.Context, req *connect.Request[v1.GetInfoRequest]) (*connect.Response[v1.GetInfoResponse], error) {
+	return c.getInfo.CallUnary(ctx, req)
+}
+
+// CreateDrop calls burnenv.v1.DropService.CreateDrop.
+func (c *dropServiceClient) CreateDrop(ctx context.Context, req *connect.Request[v1.CreateDropRequest]) (*connect.Response[v1.CreateDropResponse], error) {
+	return c.createDrop.CallUnary(ctx, req)
+}
+
+// GetDrop calls burnenv.v1.DropService.GetDrop.
+func (c *dropServiceClient) GetDrop(ctx context.Context, req *connect.Request[v1.GetDropRequest]) (*connect.Response[v1.GetDropResponse], error) {
+	return c.getDrop.CallUnary(ctx, req)
+}
+
+// GetDropStatus calls burnenv.v1.DropService.GetDropStatus.
+func (c *dropServiceClient) GetDropStatus(ctx context.Context, req *connect.Request[v1.GetDropStatusRequest]) (*connect.Response[v1.GetDropStatusResponse], error) {
+	return c.getDropStatus.CallUnary(ctx, req)
+}
+
+// RevokeDrop calls burnenv.v1.DropService.RevokeDrop.
+func (c *dropServiceClient) RevokeDrop(ctx context.Context, req *connect.Request[v1.RevokeDropRequest]) (*connect.Response[v1.RevokeDropResponse], error) {
+	return c.revokeDrop.CallUnary(ctx, req)
+}
+
+// WatchDrop calls burnenv.v1.DropService.WatchDrop.
+func (c *dropServiceClient) WatchDrop(ctx context.Context, req *connect.Request[v1.WatchDropRequest]) (*connect.ServerStreamForClient[v1.DropEvent], error) {
+	return c.watchDrop.CallServerStream(ctx, req)
+}
+
+// DropServiceHandler is an implementation of the burnenv.v1.DropService service.
+type DropServiceHandler interface {
+	// Server version, limits and features (mirrors GET /v1/info).
+	GetInfo(context.Context, *connect.Request[v1.GetInfoRequest]) (*connect.Response[v1.GetInfoResponse], error)
+	// Store an encrypted payload. Send "Authorization: Bearer <key>" and the
+	// X-BurnEnv-Challenge / X-BurnEnv-Nonce headers when the server requires them.
+	CreateDrop(context.Context, *connect.Request[v1.CreateDropRequest]) (*connect.Response[v1.CreateDropResponse], error)
+	// Retrieve the payload and consume one view (burned after the last).
+	GetDrop(context.Context, *connect.Request[v1.GetDropRequest]) (*connect.Response[v1.GetDropResponse], error)
+	// Remaining views and expiry of a drop. Never consumes a view.
+	GetDropStatus(context.Context, *connect.Request[v1.GetDropStatusRequest]) (*connect.Response[v1.GetDropStatusResponse], error)
+	// Burn a drop without retrieving it.
+	RevokeDrop(context.Context, *connect.Request[v1.RevokeDropRequest]) (*connect.Response[v1.RevokeDropResponse], error)
+	// Stream a drop's lifecycle events until it is burned, expired or revoked.
+	// Never consumes a view.
+	WatchDrop(context.Context, *connect.Request[v1.WatchDropRequest], *connect.ServerStream[v1.DropEvent]) error
+}
+
+// NewDropServiceHandler builds an HTTP handler from the service implementation. It returns the path
+// on which to mount the handler and the handler itself.
+//
+// By default, handlers support the Connect, gRPC, and gRPC-Web protocols with the binary Protobuf
+// and JSON codecs. They also support gzip compression.
+func NewDropServiceHandler(svc DropServiceHandler, opts ...connect.HandlerOption) (string, http.Handler) {
+	dropServiceMethods := v1.File_burnenv_v1_drop_proto.Services().ByName("DropService").Methods()
+	dropServiceGetInfoHandler := connect.NewUnaryHandler(
+		DropServiceGetInfoProcedure,
+		svc.GetInfo,
+		connect.WithSchema(dropServiceMethods.ByName("GetInfo")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	dropServiceCreateDropHandler := connect.NewUnaryHandler(
+		DropServiceCreateDropProcedure,
+		svc.CreateDrop,
+		connect.WithSchema(dropServiceMethods.ByName("CreateDrop")),
+		connect.WithHandlerOptions(opts...),
+	)
+	dropServiceGetDropHandler := connect.NewUnaryHandler(
+		DropServiceGetDropProcedure,
+		svc.GetDrop,
+		connect.WithSchema(dropServiceMethods.ByName("GetDrop")),
+		connect.WithHandlerOptions(opts...),
+	)
+	dropServiceGetDropStatusHandler := connect.NewUnaryHandler(
+		DropServiceGetDropStatusProcedure,
+		svc.GetDropStatus,
+		connect.WithSchema(dropServiceMethods.ByName("GetDropStatus")),
+		connect.WithIdempotency(connect.IdempotencyNoSideEffects),
+		connect.WithHandlerOptions(opts...),
+	)
+	dropServiceRevokeDropHandler := connect.NewUnaryHandler(
+		DropServiceRevokeDropProcedure,
+		svc.RevokeDrop,
+		connect.WithSchema(dropServiceMethods.ByName("RevokeDrop")),
+		connect.WithHandlerOptions(opts...),
+	)
+	dropServiceWatchDropHandler := connect.NewServerStreamHandler(
+		DropServiceWatchDropProcedure,
+		svc.WatchDrop,
+		connect.WithSchema(dropServiceMethods.ByName("WatchDrop")),
+		connect.WithHandlerOptions(opts...),
+	)
+	return "/burnenv.v1.DropService/", http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case DropServiceGetInfoProcedure:
+			dropServiceGetInfoHandler.ServeHTTP(w, r)
+		case DropServiceCreateDropProcedure:
+			dropServiceCreateDropHandler.ServeHTTP(w, r)
+		case DropServiceGetDropProcedure:
+			dropServiceGetDropHandler.ServeHTTP(w, r)
+		case DropServiceGetDropStatusProcedure:
+			dropServiceGetDropStatusHandler.ServeHTTP(w, r)
+		case DropServiceRevokeDropProcedure:
+			dropServiceRevokeDropHandler.ServeHTTP(w, r)
+		case DropServiceWatchDropProcedure:
+			dropServiceWatchDropHandler.ServeHTTP(w, r)
+		default:
+			http.NotFound(w, r)
+		}
+	})
+}
+
+// UnimplementedDropServiceHandler returns CodeUnimplemented from all methods.
+type UnimplementedDropServiceHandler struct{}
+
+func (UnimplementedDropServiceHandler) GetInfo(context.Context, *connect.Request[v1.GetInfoRequest]) (*connect.Response[v1.GetInfoResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("burnenv.v1.DropService.GetInfo is not implemented"))
+}
+
+func (UnimplementedDropServiceHandler) CreateDrop(context.Context, *connect.Request[v1.CreateDropRequest]) (*connect.Response[v1.CreateDropResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("burnenv.v1.DropService.CreateDrop is not implemented"))
+}
+
+func (UnimplementedDropServiceHandler) GetDrop(context.Context, *connect.Request[v1.GetDropRequest]) (*connect.Response[v1.GetDropResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("burnenv.v1.DropService.GetDrop is not implemented"))
+}
+
+func (UnimplementedDropServiceHandler) GetDropStatus(context.Context, *connect.Request[v1.GetDropStatusRequest]) (*connect.Response[v1.GetDropStatusResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("burnenv.v1.DropService.GetDropStatus is not implemented"))
+}
+
+func (UnimplementedDropServiceHandler) RevokeDrop(context.Context, *connect.Request[v1.RevokeDropRequest]) (*connect.Response[v1.RevokeDropResponse], error) {
+	return nil, connect.NewError(connect.CodeUnimplemented, errors.New("burnenv.v1.DropService.RevokeDrop is not implemented"))
+}
+
+func (UnimplementedDropServiceHandler) WatchDrop(context.Context, *connect.Request[v1.WatchDropRequest], *connect.ServerStream[v1.DropEvent]) error {
+	return connect.NewError(connect.CodeUnimplemented, errors.New("burnenv.v1.DropService.WatchDrop is not implemented"))
+}
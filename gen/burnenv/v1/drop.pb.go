@@ -0,0 +1,1066 @@
+// BurnEnv RPC API, served by `burnenv serve` next to the JSON API on the
+// same port (Connect, gRPC and gRPC-Web protocols). It shares the JSON API's
+// store, validation, API keys and proof-of-work, so drops created over one
+// can be retrieved over the other.
+//
+// Regenerate with: buf generate (see buf.gen.yaml)
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.12
+// 	protoc        (unknown)
+// source: burnenv/v1/drop.proto
+
+package burnenvv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DropEvent_Kind int32
+
+const (
+	DropEvent_KIND_UNSPECIFIED DropEvent_Kind = 0
+	DropEvent_KIND_RETRIEVED   DropEvent_Kind = 1
+	DropEvent_KIND_BURNED      DropEvent_Kind = 2
+	DropEvent_KIND_EXPIRED     DropEvent_Kind = 3
+	DropEvent_KIND_REVOKED     DropEvent_Kind = 4
+)
+
+// Enum value maps for DropEvent_Kind.
+var (
+	DropEvent_Kind_name = map[int32]string{
+		0: "KIND_UNSPECIFIED",
+		1: "KIND_RETRIEVED",
+		2: "KIND_BURNED",
+		3: "KIND_EXPIRED",
+		4: "KIND_REVOKED",
+	}
+	DropEvent_Kind_value = map[string]int32{
+		"KIND_UNSPECIFIED": 0,
+		"KIND_RETRIEVED":   1,
+		"KIND_BURNED":      2,
+		"KIND_EXPIRED":     3,
+		"KIND_REVOKED":     4,
+	}
+)
+
+func (x DropEvent_Kind) Enum() *DropEvent_Kind {
+	p := new(DropEvent_Kind)
+	*p = x
+	return p
+}
+
+func (x DropEvent_Kind) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DropEvent_Kind) Descriptor() protoreflect.EnumDescriptor {
+	return file_burnenv_v1_drop_proto_enumTypes[0].Descriptor()
+}
+
+func (DropEvent_Kind) Type() protoreflect.EnumType {
+	return &file_burnenv_v1_drop_proto_enumTypes[0]
+}
+
+func (x DropEvent_Kind) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DropEvent_Kind.Descriptor instead.
+func (DropEvent_Kind) EnumDescriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{14, 0}
+}
+
+type KDFParams struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Algorithm     string                 `protobuf:"bytes,1,opt,name=algorithm,proto3" json:"algorithm,omitempty"` // "argon2id"
+	Time          uint32                 `protobuf:"varint,2,opt,name=time,proto3" json:"time,omitempty"`
+	Memory        uint32                 `protobuf:"varint,3,opt,name=memory,proto3" json:"memory,omitempty"` // KiB
+	Threads       uint32                 `protobuf:"varint,4,opt,name=threads,proto3" json:"threads,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *KDFParams) Reset() {
+	*x = KDFParams{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *KDFParams) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*KDFParams) ProtoMessage() {}
+
+func (x *KDFParams) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use KDFParams.ProtoReflect.Descriptor instead.
+func (*KDFParams) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *KDFParams) GetAlgorithm() string {
+	if x != nil {
+		return x.Algorithm
+	}
+	return ""
+}
+
+func (x *KDFParams) GetTime() uint32 {
+	if x != nil {
+		return x.Time
+	}
+	return 0
+}
+
+func (x *KDFParams) GetMemory() uint32 {
+	if x != nil {
+		return x.Memory
+	}
+	return 0
+}
+
+func (x *KDFParams) GetThreads() uint32 {
+	if x != nil {
+		return x.Threads
+	}
+	return 0
+}
+
+// Client-side encrypted secret; the server cannot decrypt it.
+type EncryptedPayload struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	Ciphertext []byte                 `protobuf:"bytes,1,opt,name=ciphertext,proto3" json:"ciphertext,omitempty"` // AES-256-GCM ciphertext with tag
+	Salt       []byte                 `protobuf:"bytes,2,opt,name=salt,proto3" json:"salt,omitempty"`
+	Iv         []byte                 `protobuf:"bytes,3,opt,name=iv,proto3" json:"iv,omitempty"` // 12-byte GCM nonce
+	Kdf        *KDFParams             `protobuf:"bytes,4,opt,name=kdf,proto3" json:"kdf,omitempty"`
+	Expiry     int64                  `protobuf:"varint,5,opt,name=expiry,proto3" json:"expiry,omitempty"` // Unix time after which the drop is burned
+	MaxViews   int32                  `protobuf:"varint,6,opt,name=max_views,json=maxViews,proto3" json:"max_views,omitempty"`
+	// MIME type of the plaintext (e.g. application/x-tar), bound to the
+	// ciphertext as GCM additional data. Empty for text.
+	ContentType   string `protobuf:"bytes,7,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EncryptedPayload) Reset() {
+	*x = EncryptedPayload{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EncryptedPayload) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EncryptedPayload) ProtoMessage() {}
+
+func (x *EncryptedPayload) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EncryptedPayload.ProtoReflect.Descriptor instead.
+func (*EncryptedPayload) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EncryptedPayload) GetCiphertext() []byte {
+	if x != nil {
+		return x.Ciphertext
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetSalt() []byte {
+	if x != nil {
+		return x.Salt
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetIv() []byte {
+	if x != nil {
+		return x.Iv
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetKdf() *KDFParams {
+	if x != nil {
+		return x.Kdf
+	}
+	return nil
+}
+
+func (x *EncryptedPayload) GetExpiry() int64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+func (x *EncryptedPayload) GetMaxViews() int32 {
+	if x != nil {
+		return x.MaxViews
+	}
+	return 0
+}
+
+func (x *EncryptedPayload) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+type GetInfoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoRequest) Reset() {
+	*x = GetInfoRequest{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoRequest) ProtoMessage() {}
+
+func (x *GetInfoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoRequest.ProtoReflect.Descriptor instead.
+func (*GetInfoRequest) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{2}
+}
+
+type Limits struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	MaxRequestBytes    int64                  `protobuf:"varint,1,opt,name=max_request_bytes,json=maxRequestBytes,proto3" json:"max_request_bytes,omitempty"`
+	MaxCiphertextBytes int64                  `protobuf:"varint,2,opt,name=max_ciphertext_bytes,json=maxCiphertextBytes,proto3" json:"max_ciphertext_bytes,omitempty"`
+	MinExpirySeconds   int64                  `protobuf:"varint,3,opt,name=min_expiry_seconds,json=minExpirySeconds,proto3" json:"min_expiry_seconds,omitempty"`
+	MaxExpirySeconds   int64                  `protobuf:"varint,4,opt,name=max_expiry_seconds,json=maxExpirySeconds,proto3" json:"max_expiry_seconds,omitempty"`
+	MinMaxViews        int32                  `protobuf:"varint,5,opt,name=min_max_views,json=minMaxViews,proto3" json:"min_max_views,omitempty"`
+	MaxMaxViews        int32                  `protobuf:"varint,6,opt,name=max_max_views,json=maxMaxViews,proto3" json:"max_max_views,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Limits) Reset() {
+	*x = Limits{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Limits) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Limits) ProtoMessage() {}
+
+func (x *Limits) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Limits.ProtoReflect.Descriptor instead.
+func (*Limits) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Limits) GetMaxRequestBytes() int64 {
+	if x != nil {
+		return x.MaxRequestBytes
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxCiphertextBytes() int64 {
+	if x != nil {
+		return x.MaxCiphertextBytes
+	}
+	return 0
+}
+
+func (x *Limits) GetMinExpirySeconds() int64 {
+	if x != nil {
+		return x.MinExpirySeconds
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxExpirySeconds() int64 {
+	if x != nil {
+		return x.MaxExpirySeconds
+	}
+	return 0
+}
+
+func (x *Limits) GetMinMaxViews() int32 {
+	if x != nil {
+		return x.MinMaxViews
+	}
+	return 0
+}
+
+func (x *Limits) GetMaxMaxViews() int32 {
+	if x != nil {
+		return x.MaxMaxViews
+	}
+	return 0
+}
+
+type GetInfoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Version       string                 `protobuf:"bytes,1,opt,name=version,proto3" json:"version,omitempty"`
+	KdfAlgorithms []string               `protobuf:"bytes,2,rep,name=kdf_algorithms,json=kdfAlgorithms,proto3" json:"kdf_algorithms,omitempty"`
+	Limits        *Limits                `protobuf:"bytes,3,opt,name=limits,proto3" json:"limits,omitempty"`
+	Features      []string               `protobuf:"bytes,4,rep,name=features,proto3" json:"features,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetInfoResponse) Reset() {
+	*x = GetInfoResponse{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetInfoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetInfoResponse) ProtoMessage() {}
+
+func (x *GetInfoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetInfoResponse.ProtoReflect.Descriptor instead.
+func (*GetInfoResponse) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetInfoResponse) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *GetInfoResponse) GetKdfAlgorithms() []string {
+	if x != nil {
+		return x.KdfAlgorithms
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetLimits() *Limits {
+	if x != nil {
+		return x.Limits
+	}
+	return nil
+}
+
+func (x *GetInfoResponse) GetFeatures() []string {
+	if x != nil {
+		return x.Features
+	}
+	return nil
+}
+
+type CreateDropRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       *EncryptedPayload      `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDropRequest) Reset() {
+	*x = CreateDropRequest{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDropRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDropRequest) ProtoMessage() {}
+
+func (x *CreateDropRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDropRequest.ProtoReflect.Descriptor instead.
+func (*CreateDropRequest) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *CreateDropRequest) GetPayload() *EncryptedPayload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type CreateDropResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Link          string                 `protobuf:"bytes,2,opt,name=link,proto3" json:"link,omitempty"`                      // API link for the CLI
+	WebLink       string                 `protobuf:"bytes,3,opt,name=web_link,json=webLink,proto3" json:"web_link,omitempty"` // Browser retrieval page
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateDropResponse) Reset() {
+	*x = CreateDropResponse{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateDropResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateDropResponse) ProtoMessage() {}
+
+func (x *CreateDropResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateDropResponse.ProtoReflect.Descriptor instead.
+func (*CreateDropResponse) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *CreateDropResponse) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *CreateDropResponse) GetLink() string {
+	if x != nil {
+		return x.Link
+	}
+	return ""
+}
+
+func (x *CreateDropResponse) GetWebLink() string {
+	if x != nil {
+		return x.WebLink
+	}
+	return ""
+}
+
+type GetDropRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDropRequest) Reset() {
+	*x = GetDropRequest{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDropRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDropRequest) ProtoMessage() {}
+
+func (x *GetDropRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDropRequest.ProtoReflect.Descriptor instead.
+func (*GetDropRequest) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetDropRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetDropResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Payload       *EncryptedPayload      `protobuf:"bytes,1,opt,name=payload,proto3" json:"payload,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDropResponse) Reset() {
+	*x = GetDropResponse{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDropResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDropResponse) ProtoMessage() {}
+
+func (x *GetDropResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDropResponse.ProtoReflect.Descriptor instead.
+func (*GetDropResponse) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetDropResponse) GetPayload() *EncryptedPayload {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+type GetDropStatusRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetDropStatusRequest) Reset() {
+	*x = GetDropStatusRequest{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDropStatusRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDropStatusRequest) ProtoMessage() {}
+
+func (x *GetDropStatusRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDropStatusRequest.ProtoReflect.Descriptor instead.
+func (*GetDropStatusRequest) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetDropStatusRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type GetDropStatusResponse struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	ViewsRemaining int32                  `protobuf:"varint,1,opt,name=views_remaining,json=viewsRemaining,proto3" json:"views_remaining,omitempty"`
+	MaxViews       int32                  `protobuf:"varint,2,opt,name=max_views,json=maxViews,proto3" json:"max_views,omitempty"`
+	Expiry         int64                  `protobuf:"varint,3,opt,name=expiry,proto3" json:"expiry,omitempty"` // Unix time after which the drop is burned
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *GetDropStatusResponse) Reset() {
+	*x = GetDropStatusResponse{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetDropStatusResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetDropStatusResponse) ProtoMessage() {}
+
+func (x *GetDropStatusResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetDropStatusResponse.ProtoReflect.Descriptor instead.
+func (*GetDropStatusResponse) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetDropStatusResponse) GetViewsRemaining() int32 {
+	if x != nil {
+		return x.ViewsRemaining
+	}
+	return 0
+}
+
+func (x *GetDropStatusResponse) GetMaxViews() int32 {
+	if x != nil {
+		return x.MaxViews
+	}
+	return 0
+}
+
+func (x *GetDropStatusResponse) GetExpiry() int64 {
+	if x != nil {
+		return x.Expiry
+	}
+	return 0
+}
+
+type RevokeDropRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeDropRequest) Reset() {
+	*x = RevokeDropRequest{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeDropRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeDropRequest) ProtoMessage() {}
+
+func (x *RevokeDropRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeDropRequest.ProtoReflect.Descriptor instead.
+func (*RevokeDropRequest) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *RevokeDropRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type RevokeDropResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeDropResponse) Reset() {
+	*x = RevokeDropResponse{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeDropResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeDropResponse) ProtoMessage() {}
+
+func (x *RevokeDropResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeDropResponse.ProtoReflect.Descriptor instead.
+func (*RevokeDropResponse) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{12}
+}
+
+type WatchDropRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WatchDropRequest) Reset() {
+	*x = WatchDropRequest{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WatchDropRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WatchDropRequest) ProtoMessage() {}
+
+func (x *WatchDropRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WatchDropRequest.ProtoReflect.Descriptor instead.
+func (*WatchDropRequest) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *WatchDropRequest) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+type DropEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Kind          DropEvent_Kind         `protobuf:"varint,1,opt,name=kind,proto3,enum=burnenv.v1.DropEvent_Kind" json:"kind,omitempty"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=time,proto3" json:"time,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DropEvent) Reset() {
+	*x = DropEvent{}
+	mi := &file_burnenv_v1_drop_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DropEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DropEvent) ProtoMessage() {}
+
+func (x *DropEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_burnenv_v1_drop_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DropEvent.ProtoReflect.Descriptor instead.
+func (*DropEvent) Descriptor() ([]byte, []int) {
+	return file_burnenv_v1_drop_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *DropEvent) GetKind() DropEvent_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return DropEvent_KIND_UNSPECIFIED
+}
+
+func (x *DropEvent) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+var File_burnenv_v1_drop_proto protoreflect.FileDescriptor
+
+const file_burnenv_v1_drop_proto_rawDesc = "" +
+	"\n" +
+	"\x15burnenv/v1/drop.proto\x12\n" +
+	"burnenv.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"o\n" +
+	"\tKDFParams\x12\x1c\n" +
+	"\talgorithm\x18\x01 \x01(\tR\talgorithm\x12\x12\n" +
+	"\x04time\x18\x02 \x01(\rR\x04time\x12\x16\n" +
+	"\x06memory\x18\x03 \x01(\rR\x06memory\x12\x18\n" +
+	"\athreads\x18\x04 \x01(\rR\athreads\"\xd7\x01\n" +
+	"\x10EncryptedPayload\x12\x1e\n" +
+	"\n" +
+	"ciphertext\x18\x01 \x01(\fR\n" +
+	"ciphertext\x12\x12\n" +
+	"\x04salt\x18\x02 \x01(\fR\x04salt\x12\x0e\n" +
+	"\x02iv\x18\x03 \x01(\fR\x02iv\x12'\n" +
+	"\x03kdf\x18\x04 \x01(\v2\x15.burnenv.v1.KDFParamsR\x03kdf\x12\x16\n" +
+	"\x06expiry\x18\x05 \x01(\x03R\x06expiry\x12\x1b\n" +
+	"\tmax_views\x18\x06 \x01(\x05R\bmaxViews\x12!\n" +
+	"\fcontent_type\x18\a \x01(\tR\vcontentType\"\x10\n" +
+	"\x0eGetInfoRequest\"\x8a\x02\n" +
+	"\x06Limits\x12*\n" +
+	"\x11max_request_bytes\x18\x01 \x01(\x03R\x0fmaxRequestBytes\x120\n" +
+	"\x14max_ciphertext_bytes\x18\x02 \x01(\x03R\x12maxCiphertextBytes\x12,\n" +
+	"\x12min_expiry_seconds\x18\x03 \x01(\x03R\x10minExpirySeconds\x12,\n" +
+	"\x12max_expiry_seconds\x18\x04 \x01(\x03R\x10maxExpirySeconds\x12\"\n" +
+	"\rmin_max_views\x18\x05 \x01(\x05R\vminMaxViews\x12\"\n" +
+	"\rmax_max_views\x18\x06 \x01(\x05R\vmaxMaxViews\"\x9a\x01\n" +
+	"\x0fGetInfoResponse\x12\x18\n" +
+	"\aversion\x18\x01 \x01(\tR\aversion\x12%\n" +
+	"\x0ekdf_algorithms\x18\x02 \x03(\tR\rkdfAlgorithms\x12*\n" +
+	"\x06limits\x18\x03 \x01(\v2\x12.burnenv.v1.LimitsR\x06limits\x12\x1a\n" +
+	"\bfeatures\x18\x04 \x03(\tR\bfeatures\"K\n" +
+	"\x11CreateDropRequest\x126\n" +
+	"\apayload\x18\x01 \x01(\v2\x1c.burnenv.v1.EncryptedPayloadR\apayload\"S\n" +
+	"\x12CreateDropResponse\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04link\x18\x02 \x01(\tR\x04link\x12\x19\n" +
+	"\bweb_link\x18\x03 \x01(\tR\awebLink\" \n" +
+	"\x0eGetDropRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"I\n" +
+	"\x0fGetDropResponse\x126\n" +
+	"\apayload\x18\x01 \x01(\v2\x1c.burnenv.v1.EncryptedPayloadR\apayload\"&\n" +
+	"\x14GetDropStatusRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"u\n" +
+	"\x15GetDropStatusResponse\x12'\n" +
+	"\x0fviews_remaining\x18\x01 \x01(\x05R\x0eviewsRemaining\x12\x1b\n" +
+	"\tmax_views\x18\x02 \x01(\x05R\bmaxViews\x12\x16\n" +
+	"\x06expiry\x18\x03 \x01(\x03R\x06expiry\"#\n" +
+	"\x11RevokeDropRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\x14\n" +
+	"\x12RevokeDropResponse\"\"\n" +
+	"\x10WatchDropRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\"\xd2\x01\n" +
+	"\tDropEvent\x12.\n" +
+	"\x04kind\x18\x01 \x01(\x0e2\x1a.burnenv.v1.DropEvent.KindR\x04kind\x12.\n" +
+	"\x04time\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\"e\n" +
+	"\x04Kind\x12\x14\n" +
+	"\x10KIND_UNSPECIFIED\x10\x00\x12\x12\n" +
+	"\x0eKIND_RETRIEVED\x10\x01\x12\x0f\n" +
+	"\vKIND_BURNED\x10\x02\x12\x10\n" +
+	"\fKIND_EXPIRED\x10\x03\x12\x10\n" +
+	"\fKIND_REVOKED\x10\x042\xd3\x03\n" +
+	"\vDropService\x12G\n" +
+	"\aGetInfo\x12\x1a.burnenv.v1.GetInfoRequest\x1a\x1b.burnenv.v1.GetInfoResponse\"\x03\x90\x02\x01\x12K\n" +
+	"\n" +
+	"CreateDrop\x12\x1d.burnenv.v1.CreateDropRequest\x1a\x1e.burnenv.v1.CreateDropResponse\x12B\n" +
+	"\aGetDrop\x12\x1a.burnenv.v1.GetDropRequest\x1a\x1b.burnenv.v1.GetDropResponse\x12Y\n" +
+	"\rGetDropStatus\x12 .burnenv.v1.GetDropStatusRequest\x1a!.burnenv.v1.GetDropStatusResponse\"\x03\x90\x02\x01\x12K\n" +
+	"\n" +
+	"RevokeDrop\x12\x1d.burnenv.v1.RevokeDropRequest\x1a\x1e.burnenv.v1.RevokeDropResponse\x12B\n" +
+	"\tWatchDrop\x12\x1c.burnenv.v1.WatchDropRequest\x1a\x15.burnenv.v1.DropEvent0\x01B5Z3github.com/yesahem/burnenv/gen/burnenv/v1;burnenvv1b\x06proto3"
+
+var (
+	file_burnenv_v1_drop_proto_rawDescOnce sync.Once
+	file_burnenv_v1_drop_proto_rawDescData []byte
+)
+
+func file_burnenv_v1_drop_proto_rawDescGZIP() []byte {
+	file_burnenv_v1_drop_proto_rawDescOnce.Do(func() {
+		file_burnenv_v1_drop_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_burnenv_v1_drop_proto_rawDesc), len(file_burnenv_v1_drop_proto_rawDesc)))
+	})
+	return file_burnenv_v1_drop_proto_rawDescData
+}
+
+var file_burnenv_v1_drop_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_burnenv_v1_drop_proto_msgTypes = make([]protoimpl.MessageInfo, 15)
+var file_burnenv_v1_drop_proto_goTypes = []any{
+	(DropEvent_Kind)(0),           // 0: burnenv.v1.DropEvent.Kind
+	(*KDFParams)(nil),             // 1: burnenv.v1.KDFParams
+	(*EncryptedPayload)(nil),      // 2: burnenv.v1.EncryptedPayload
+	(*GetInfoRequest)(nil),        // 3: burnenv.v1.GetInfoRequest
+	(*Limits)(nil),                // 4: burnenv.v1.Limits
+	(*GetInfoResponse)(nil),       // 5: burnenv.v1.GetInfoResponse
+	(*CreateDropRequest)(nil),     // 6: burnenv.v1.CreateDropRequest
+	(*CreateDropResponse)(nil),    // 7: burnenv.v1.CreateDropResponse
+	(*GetDropRequest)(nil),        // 8: burnenv.v1.GetDropRequest
+	(*GetDropResponse)(nil),       // 9: burnenv.v1.GetDropResponse
+	(*GetDropStatusRequest)(nil),  // 10: burnenv.v1.GetDropStatusRequest
+	(*GetDropStatusResponse)(nil), // 11: burnenv.v1.GetDropStatusResponse
+	(*RevokeDropRequest)(nil),     // 12: burnenv.v1.RevokeDropRequest
+	(*RevokeDropResponse)(nil),    // 13: burnenv.v1.RevokeDropResponse
+	(*WatchDropRequest)(nil),      // 14: burnenv.v1.WatchDropRequest
+	(*DropEvent)(nil),             // 15: burnenv.v1.DropEvent
+	(*timestamppb.Timestamp)(nil), // 16: google.protobuf.Timestamp
+}
+var file_burnenv_v1_drop_proto_depIdxs = []int32{
+	1,  // 0: burnenv.v1.EncryptedPayload.kdf:type_name -> burnenv.v1.KDFParams
+	4,  // 1: burnenv.v1.GetInfoResponse.limits:type_name -> burnenv.v1.Limits
+	2,  // 2: burnenv.v1.CreateDropRequest.payload:type_name -> burnenv.v1.EncryptedPayload
+	2,  // 3: burnenv.v1.GetDropResponse.payload:type_name -> burnenv.v1.EncryptedPayload
+	0,  // 4: burnenv.v1.DropEvent.kind:type_name -> burnenv.v1.DropEvent.Kind
+	16, // 5: burnenv.v1.DropEvent.time:type_name -> google.protobuf.Timestamp
+	3,  // 6: burnenv.v1.DropService.GetInfo:input_type -> burnenv.v1.GetInfoRequest
+	6,  // 7: burnenv.v1.DropService.CreateDrop:input_type -> burnenv.v1.CreateDropRequest
+	8,  // 8: burnenv.v1.DropService.GetDrop:input_type -> burnenv.v1.GetDropRequest
+	10, // 9: burnenv.v1.DropService.GetDropStatus:input_type -> burnenv.v1.GetDropStatusRequest
+	12, // 10: burnenv.v1.DropService.RevokeDrop:input_type -> burnenv.v1.RevokeDropRequest
+	14, // 11: burnenv.v1.DropService.WatchDrop:input_type -> burnenv.v1.WatchDropRequest
+	5,  // 12: burnenv.v1.DropService.GetInfo:output_type -> burnenv.v1.GetInfoResponse
+	7,  // 13: burnenv.v1.DropService.CreateDrop:output_type -> burnenv.v1.CreateDropResponse
+	9,  // 14: burnenv.v1.DropService.GetDrop:output_type -> burnenv.v1.GetDropResponse
+	11, // 15: burnenv.v1.DropService.GetDropStatus:output_type -> burnenv.v1.GetDropStatusResponse
+	13, // 16: burnenv.v1.DropService.RevokeDrop:output_type -> burnenv.v1.RevokeDropResponse
+	15, // 17: burnenv.v1.DropService.WatchDrop:output_type -> burnenv.v1.DropEvent
+	12, // [12:18] is the sub-list for method output_type
+	6,  // [6:12] is the sub-list for method input_type
+	6,  // [6:6] is the sub-list for extension type_name
+	6,  // [6:6] is the sub-list for extension extendee
+	0,  // [0:6] is the sub-list for field type_name
+}
+
+func init() { file_burnenv_v1_drop_proto_init() }
+func file_burnenv_v1_drop_proto_init() {
+	if File_burnenv_v1_drop_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_burnenv_v1_drop_proto_rawDesc), len(file_burnenv_v1_drop_proto_rawDesc)),
+			NumEnums:      1,
+			NumMessages:   15,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_burnenv_v1_drop_proto_goTypes,
+		DependencyIndexes: file_burnenv_v1_drop_proto_depIdxs,
+		EnumInfos:         file_burnenv_v1_drop_proto_enumTypes,
+		MessageInfos:      file_burnenv_v1_drop_proto_msgTypes,
+	}.Build()
+	File_burnenv_v1_drop_proto = out.File
+	file_burnenv_v1_drop_proto_goTypes = nil
+	file_burnenv_v1_drop_proto_depIdxs = nil
+}
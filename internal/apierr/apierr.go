@@ -0,0 +1,54 @@
+// Package apierr defines the stable, machine-readable error codes returned
+// in the "code" field of API error responses and reported by the CLI.
+// Messages may change wording; codes never do.
+package apierr
+
+import "net/http"
+
+// Code is a stable error identifier.
+type Code string
+
+// Codes returned by the server.
+const (
+	BadRequest   Code = "bad_request"  // Malformed or invalid request
+	TooLarge     Code = "too_large"    // Body or ciphertext over the limit
+	NotFound     Code = "not_found"    // Never existed, or already burned
+	Expired      Code = "expired"      // TTL passed; burned
+	Exhausted    Code = "exhausted"    // Max views or code attempts used up; burned
+	Unauthorized Code = "unauthorized" // API key missing or invalid
+	Forbidden    Code = "forbidden"    // API key policy or CORS origin refused
+	PoWFailed    Code = "pow_failed"   // Proof-of-work missing, stale or wrong
+	RateLimited  Code = "rate_limited" // Too many requests
+	Internal     Code = "internal"     // Anything else
+)
+
+// Codes produced only by the CLI.
+const (
+	BadPassword  Code = "bad_password"  // Decryption failed (wrong password or code)
+	Usage        Code = "usage"         // Invalid arguments or flags
+	BadFormat    Code = "bad_format"    // Secret is not valid dotenv where one is required
+	Conflict     Code = "conflict"      // Merge refused: keys differ (--on-conflict=fail)
+	WeakPassword Code = "weak_password" // Below create --min-strength
+)
+
+// ForStatus returns the code for an HTTP status when no specific code is
+// known (validation failures, responses from servers without codes).
+func ForStatus(status int) Code {
+	switch status {
+	case http.StatusBadRequest:
+		return BadRequest
+	case http.StatusRequestEntityTooLarge:
+		return TooLarge
+	case http.StatusNotFound:
+		return NotFound
+	case http.StatusGone:
+		return Exhausted
+	case http.StatusUnauthorized:
+		return Unauthorized
+	case http.StatusForbidden:
+		return Forbidden
+	case http.StatusTooManyRequests:
+		return RateLimited
+	}
+	return Internal
+}
@@ -0,0 +1,223 @@
+// Package archive packs files and directories into a tar for sharing
+// (burnenv create --file/--dir) and restores them safely on the other
+// side: entries can't escape the target directory, links are rejected
+// and existing files are never overwritten.
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// File describes one archive entry.
+type File struct {
+	Name string // Slash-separated, relative
+	Mode fs.FileMode
+	Size int64
+	Dir  bool
+}
+
+// Pack archives files (stored under their base names) and dirs (stored
+// recursively under their base names), keeping permission bits. Symlinks
+// and other special files are refused rather than followed.
+func Pack(files, dirs []string) ([]byte, error) {
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	seen := make(map[string]bool)
+	add := func(name, src string, info fs.FileInfo) error {
+		if seen[name] {
+			return fmt.Errorf("%s: duplicate name %q in archive", src, name)
+		}
+		seen[name] = true
+		switch {
+		case info.IsDir():
+			return tw.WriteHeader(&tar.Header{Typeflag: tar.TypeDir, Name: name + "/", Mode: int64(info.Mode().Perm()), ModTime: info.ModTime()})
+		case info.Mode().IsRegular():
+			data, err := os.ReadFile(src)
+			if err != nil {
+				return err
+			}
+			if err := tw.WriteHeader(&tar.Header{Typeflag: tar.TypeReg, Name: name, Mode: int64(info.Mode().Perm()), Size: int64(len(data)), ModTime: info.ModTime()}); err != nil {
+				return err
+			}
+			_, err = tw.Write(data)
+			return err
+		}
+		return fmt.Errorf("%s: only regular files and directories can be shared (got %s)", src, info.Mode().Type())
+	}
+
+	for _, f := range files {
+		info, err := os.Lstat(f)
+		if err != nil {
+			return nil, err
+		}
+		if info.IsDir() {
+			return nil, fmt.Errorf("%s is a directory (use --dir)", f)
+		}
+		if err := add(filepath.Base(f), f, info); err != nil {
+			return nil, err
+		}
+	}
+	for _, d := range dirs {
+		info, err := os.Lstat(d)
+		if err != nil {
+			return nil, err
+		}
+		if !info.IsDir() {
+			return nil, fmt.Errorf("%s is not a directory (use --file)", d)
+		}
+		// Name the root after the real directory, so "." and "../x" work
+		abs, err := filepath.Abs(d)
+		if err != nil {
+			return nil, err
+		}
+		base := filepath.Base(abs)
+		if base == "." || base == ".." || base == string(filepath.Separator) {
+			return nil, fmt.Errorf("%s: can't share a filesystem root; name a directory inside it", d)
+		}
+		err = filepath.WalkDir(d, func(p string, e fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			rel, err := filepath.Rel(d, p)
+			if err != nil {
+				return err
+			}
+			info, err := e.Info()
+			if err != nil {
+				return err
+			}
+			return add(path.Join(base, filepath.ToSlash(rel)), p, info)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// List returns the entries of a tar archive without extracting it.
+func List(data []byte) ([]File, error) {
+	var files []File
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return files, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("invalid archive: %w", err)
+		}
+		files = append(files, File{
+			Name: strings.TrimSuffix(h.Name, "/"),
+			Mode: fs.FileMode(h.Mode).Perm(),
+			Size: h.Size,
+			Dir:  h.Typeflag == tar.TypeDir,
+		})
+	}
+}
+
+// Extract restores a tar archive under dir, creating dir if needed, and
+// returns the files written. Absolute or ".." paths, links and device
+// entries are rejected, and all writes go through an os.Root so symlinks
+// already in dir can't redirect them. Existing files are not replaced.
+// Permission bits are kept, minus setuid/setgid/sticky.
+func Extract(data []byte, dir string) ([]File, error) {
+	if err := os.MkdirAll(dir, 0o700); err != nil {
+		return nil, err
+	}
+	root, err := os.OpenRoot(dir)
+	if err != nil {
+		return nil, err
+	}
+	defer root.Close()
+
+	var written []File
+	tr := tar.NewReader(bytes.NewReader(data))
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return written, nil
+		}
+		if err != nil {
+			return written, fmt.Errorf("invalid archive: %w", err)
+		}
+		// Archives made with "tar -C dir ." start with "./"; dir already exists
+		if h.Typeflag == tar.TypeDir && path.Clean(strings.ReplaceAll(h.Name, `\`, "/")) == "." {
+			continue
+		}
+		name, err := safeName(h.Name)
+		if err != nil {
+			return written, err
+		}
+		mode := fs.FileMode(h.Mode).Perm()
+		switch h.Typeflag {
+		case tar.TypeDir:
+			if err := mkdirAll(root, name, mode|0o700); err != nil {
+				return written, err
+			}
+			written = append(written, File{Name: name, Mode: mode, Dir: true})
+		case tar.TypeReg:
+			if err := mkdirAll(root, path.Dir(name), 0o700); err != nil {
+				return written, err
+			}
+			f, err := root.OpenFile(filepath.FromSlash(name), os.O_WRONLY|os.O_CREATE|os.O_EXCL, mode)
+			if errors.Is(err, fs.ErrExist) {
+				return written, fmt.Errorf("%s already exists in %s; not overwriting", name, dir)
+			}
+			if err != nil {
+				return written, err
+			}
+			n, err := io.Copy(f, tr)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				return written, err
+			}
+			written = append(written, File{Name: name, Mode: mode, Size: n})
+		default:
+			return written, fmt.Errorf("%s: links and special files are not extracted", h.Name)
+		}
+	}
+}
+
+// safeName cleans an entry name and rejects anything that would land
+// outside the extraction directory.
+func safeName(name string) (string, error) {
+	clean := path.Clean(strings.ReplaceAll(name, `\`, "/"))
+	if clean == "." || path.IsAbs(clean) || clean == ".." || strings.HasPrefix(clean, "../") || filepath.VolumeName(clean) != "" {
+		return "", fmt.Errorf("unsafe path %q in archive", name)
+	}
+	return clean, nil
+}
+
+// mkdirAll creates name and its parents inside root.
+func mkdirAll(root *os.Root, name string, mode fs.FileMode) error {
+	if name == "." {
+		return nil
+	}
+	if err := mkdirAll(root, path.Dir(name), 0o700); err != nil {
+		return err
+	}
+	err := root.Mkdir(filepath.FromSlash(name), mode)
+	if errors.Is(err, fs.ErrExist) {
+		info, serr := root.Lstat(filepath.FromSlash(name))
+		if serr == nil && info.IsDir() {
+			return nil
+		}
+		return fmt.Errorf("%s exists and is not a directory", name)
+	}
+	return err
+}
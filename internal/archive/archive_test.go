@@ -0,0 +1,247 @@
+package archive
+
+import (
+	"archive/tar"
+	"bytes"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"testing"
+)
+
+// entry is one header of a hand-made tar stream.
+type entry struct {
+	name     string
+	typeflag byte
+	body     string
+	link     string
+}
+
+func makeTar(t *testing.T, entries ...entry) []byte {
+	t.Helper()
+	var buf bytes.Buffer
+	tw := tar.NewWriter(&buf)
+	for _, e := range entries {
+		h := &tar.Header{Name: e.name, Typeflag: e.typeflag, Mode: 0o644, Size: int64(len(e.body)), Linkname: e.link}
+		if e.typeflag != tar.TypeReg {
+			h.Size = 0
+		}
+		if err := tw.WriteHeader(h); err != nil {
+			t.Fatal(err)
+		}
+		if h.Size > 0 {
+			tw.Write([]byte(e.body))
+		}
+	}
+	if err := tw.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return buf.Bytes()
+}
+
+func TestExtractRejects(t *testing.T) {
+	for _, tc := range []struct {
+		name    string
+		entries []entry
+		errHas  string
+	}{
+		{"dotdot", []entry{{name: "../evil", typeflag: tar.TypeReg, body: "x"}}, "unsafe path"},
+		{"nested dotdot", []entry{{name: "a/../../evil", typeflag: tar.TypeReg, body: "x"}}, "unsafe path"},
+		{"backslash dotdot", []entry{{name: `..\evil`, typeflag: tar.TypeReg, body: "x"}}, "unsafe path"},
+		{"absolute", []entry{{name: "/tmp/evil", typeflag: tar.TypeReg, body: "x"}}, "unsafe path"},
+		{"dotdot dir", []entry{{name: "../", typeflag: tar.TypeDir}}, "unsafe path"},
+		{"symlink", []entry{{name: "link", typeflag: tar.TypeSymlink, link: "/etc/passwd"}}, "links and special files"},
+		{"hard link", []entry{{name: "link", typeflag: tar.TypeLink, link: "../evil"}}, "links and special files"},
+		{"char device", []entry{{name: "null", typeflag: tar.TypeChar}}, "links and special files"},
+		{"fifo", []entry{{name: "fifo", typeflag: tar.TypeFifo}}, "links and special files"},
+		{"duplicate file", []entry{
+			{name: "a.env", typeflag: tar.TypeReg, body: "first"},
+			{name: "a.env", typeflag: tar.TypeReg, body: "second"},
+		}, "not overwriting"},
+		{"file as parent", []entry{
+			{name: "a", typeflag: tar.TypeReg, body: "x"},
+			{name: "a/b", typeflag: tar.TypeReg, body: "x"},
+		}, "not a directory"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			parent := t.TempDir()
+			dir := filepath.Join(parent, "out")
+			_, err := Extract(makeTar(t, tc.entries...), dir)
+			if err == nil || !strings.Contains(err.Error(), tc.errHas) {
+				t.Fatalf("got %v, want an error containing %q", err, tc.errHas)
+			}
+			if _, err := os.Lstat(filepath.Join(parent, "evil")); err == nil {
+				t.Error("entry escaped the extraction directory")
+			}
+			if data, _ := os.ReadFile(filepath.Join(dir, "a.env")); string(data) == "second" {
+				t.Error("existing file was overwritten")
+			}
+		})
+	}
+}
+
+func TestExtractKeepsExistingFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, ".env"), []byte("mine"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Extract(makeTar(t, entry{name: ".env", typeflag: tar.TypeReg, body: "theirs"}), dir); err == nil {
+		t.Fatal("existing file replaced without an error")
+	}
+	if data, _ := os.ReadFile(filepath.Join(dir, ".env")); string(data) != "mine" {
+		t.Errorf(".env = %q, want it untouched", data)
+	}
+}
+
+// TestExtractThroughSymlink checks a symlink already in the target can't
+// redirect writes outside it.
+func TestExtractThroughSymlink(t *testing.T) {
+	parent := t.TempDir()
+	dir := filepath.Join(parent, "out")
+	outside := filepath.Join(parent, "outside")
+	for _, d := range []string{dir, outside} {
+		if err := os.Mkdir(d, 0o700); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Symlink(outside, filepath.Join(dir, "link")); err != nil {
+		t.Skip("symlinks unavailable:", err)
+	}
+	if _, err := Extract(makeTar(t, entry{name: "link/evil", typeflag: tar.TypeReg, body: "x"}), dir); err == nil {
+		t.Error("write through a symlink succeeded")
+	}
+	if _, err := os.Lstat(filepath.Join(outside, "evil")); err == nil {
+		t.Error("entry escaped through the symlink")
+	}
+}
+
+func TestExtractDotRoot(t *testing.T) {
+	// As made by "tar -C proj -cf - ."
+	data := makeTar(t,
+		entry{name: "./", typeflag: tar.TypeDir},
+		entry{name: "./.env", typeflag: tar.TypeReg, body: "A=1\n"},
+		entry{name: "./certs/", typeflag: tar.TypeDir},
+		entry{name: "./certs/ca.pem", typeflag: tar.TypeReg, body: "pem"},
+	)
+	dir := t.TempDir()
+	files, err := Extract(data, dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, f := range files {
+		names = append(names, f.Name)
+	}
+	if want := []string{".env", "certs", "certs/ca.pem"}; !slices.Equal(names, want) {
+		t.Errorf("extracted %q, want %q", names, want)
+	}
+	if got, _ := os.ReadFile(filepath.Join(dir, "certs", "ca.pem")); string(got) != "pem" {
+		t.Errorf("certs/ca.pem = %q", got)
+	}
+}
+
+func TestPackExtractRoundTrip(t *testing.T) {
+	src := filepath.Join(t.TempDir(), "proj")
+	for name, body := range map[string]string{
+		".env":             "A=1\n",
+		"certs/ca.pem":     "pem",
+		"certs/key.pem":    "key",
+		"empty/.gitkeep":   "",
+		"nested/a/b/c.txt": "deep",
+	} {
+		p := filepath.Join(src, filepath.FromSlash(name))
+		if err := os.MkdirAll(filepath.Dir(p), 0o755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(p, []byte(body), 0o644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := os.Chmod(filepath.Join(src, "certs", "key.pem"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	single := filepath.Join(t.TempDir(), "token.txt")
+	if err := os.WriteFile(single, []byte("tok"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	// ".", "./" and "../proj" are all named after the directory itself
+	t.Chdir(src)
+	for _, d := range []string{".", "./", "../proj", src} {
+		t.Run(d, func(t *testing.T) {
+			data, err := Pack([]string{single}, []string{d})
+			if err != nil {
+				t.Fatal(err)
+			}
+			listed, err := List(data)
+			if err != nil {
+				t.Fatal(err)
+			}
+			for _, f := range listed {
+				if f.Name != "token.txt" && f.Name != "proj" && !strings.HasPrefix(f.Name, "proj/") {
+					t.Errorf("entry %q is not under proj/", f.Name)
+				}
+			}
+
+			out := t.TempDir()
+			files, err := Extract(data, out)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(files) != len(listed) {
+				t.Errorf("extracted %d entries, listed %d", len(files), len(listed))
+			}
+			for name, body := range map[string]string{
+				"token.txt":             "tok",
+				"proj/.env":             "A=1\n",
+				"proj/certs/key.pem":    "key",
+				"proj/empty/.gitkeep":   "",
+				"proj/nested/a/b/c.txt": "deep",
+			} {
+				got, err := os.ReadFile(filepath.Join(out, filepath.FromSlash(name)))
+				if err != nil || string(got) != body {
+					t.Errorf("%s = %q, %v; want %q", name, got, err, body)
+				}
+			}
+			if runtime.GOOS != "windows" {
+				info, err := os.Stat(filepath.Join(out, "proj", "certs", "key.pem"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if mode := info.Mode().Perm(); mode != 0o600 {
+					t.Errorf("key.pem mode %v, want 0600", mode)
+				}
+			}
+		})
+	}
+}
+
+func TestPackRefuses(t *testing.T) {
+	if _, err := Pack(nil, []string{string(filepath.Separator)}); err == nil || !strings.Contains(err.Error(), "filesystem root") {
+		t.Errorf("packing / : got %v", err)
+	}
+
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "a"), nil, 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := Pack([]string{dir}, nil); err == nil {
+		t.Error("directory accepted as a file")
+	}
+	if _, err := Pack(nil, []string{filepath.Join(dir, "a")}); err == nil {
+		t.Error("file accepted as a directory")
+	}
+	if _, err := Pack([]string{filepath.Join(dir, "a"), filepath.Join(dir, "a")}, nil); err == nil {
+		t.Error("duplicate name accepted")
+	}
+	if err := os.Symlink("/etc/passwd", filepath.Join(dir, "link")); err == nil {
+		if _, err := Pack(nil, []string{dir}); err == nil {
+			t.Error("symlink packed")
+		}
+		if _, err := Pack([]string{filepath.Join(dir, "link")}, nil); err == nil {
+			t.Error("symlink packed as a file")
+		}
+	}
+}
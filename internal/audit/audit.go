@@ -0,0 +1,157 @@
+// Package audit writes and verifies an append-only log of drop lifecycle
+// events. Each JSON line carries the SHA-256 of the previous line's hash
+// and its own fields, so editing, reordering or deleting any entry breaks
+// the chain from that point on.
+//
+// The chain is unkeyed: it detects accidental corruption and careless
+// edits, not a deliberate forger, who can recompute every hash after the
+// change. Keep a copy of the head hash (or the log itself) somewhere the
+// server cannot write to if the log must stand up to tampering.
+//
+// Entries never contain secrets, drop IDs (only hashes) or reader identity.
+package audit
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// genesis is the "previous hash" of the first entry.
+var genesis = strings.Repeat("0", 64)
+
+// Entry is one audit record.
+type Entry struct {
+	Seq   uint64    `json:"seq"`
+	Time  time.Time `json:"time"`
+	Event string    `json:"event"`         // created, retrieved, burned, expired, revoked
+	Drop  string    `json:"drop"`          // Hashed drop ID
+	Key   string    `json:"key,omitempty"` // API key name that created the drop
+	Prev  string    `json:"prev"`
+	Hash  string    `json:"hash"`
+}
+
+// digest computes the entry hash over prev and every field except Hash.
+func (e Entry) digest() string {
+	e.Hash = ""
+	body, _ := json.Marshal(e)
+	sum := sha256.Sum256(append([]byte(e.Prev+"\n"), body...))
+	return hex.EncodeToString(sum[:])
+}
+
+// Log appends entries to a file, continuing an existing chain.
+type Log struct {
+	mu   sync.Mutex
+	f    *os.File
+	seq  uint64
+	head string
+}
+
+// Open opens (or creates) an audit log for appending. An existing file's
+// chain is verified first so new entries never extend a broken chain.
+func Open(path string) (*Log, error) {
+	f, err := os.OpenFile(path, os.O_RDWR|os.O_CREATE|os.O_APPEND, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("open audit log: %w", err)
+	}
+	res, err := Verify(f)
+	if err != nil {
+		f.Close()
+		return nil, fmt.Errorf("audit log %s: %w", path, err)
+	}
+	return &Log{f: f, seq: res.Entries, head: res.Head}, nil
+}
+
+// Record appends one entry.
+func (l *Log) Record(event, drop, key string, t time.Time) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	e := Entry{Seq: l.seq + 1, Time: t.UTC(), Event: event, Drop: drop, Key: key, Prev: l.head}
+	e.Hash = e.digest()
+	line, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	if _, err := l.f.Write(append(line, '\n')); err != nil {
+		return fmt.Errorf("write audit log: %w", err)
+	}
+	l.seq, l.head = e.Seq, e.Hash
+	return nil
+}
+
+// Close flushes and closes the file.
+func (l *Log) Close() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if err := l.f.Sync(); err != nil {
+		l.f.Close()
+		return err
+	}
+	return l.f.Close()
+}
+
+// Result summarizes a verified chain.
+type Result struct {
+	Entries uint64
+	Head    string // Hash of the last entry (genesis for an empty log)
+}
+
+// Verify checks every entry of the chain read from r.
+func Verify(r io.Reader) (Result, error) {
+	res := Result{Head: genesis}
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	line := 0
+	for sc.Scan() {
+		line++
+		raw := bytes.TrimSpace(sc.Bytes())
+		if len(raw) == 0 {
+			continue
+		}
+		var e Entry
+		dec := json.NewDecoder(bytes.NewReader(raw))
+		dec.DisallowUnknownFields()
+		if err := dec.Decode(&e); err != nil {
+			return res, fmt.Errorf("line %d: invalid entry: %w", line, err)
+		}
+		switch {
+		case e.Seq != res.Entries+1:
+			return res, fmt.Errorf("line %d: sequence %d, expected %d (entry missing or reordered)", line, e.Seq, res.Entries+1)
+		case e.Prev != res.Head:
+			return res, fmt.Errorf("line %d: previous-hash mismatch (chain broken)", line)
+		case e.Hash != e.digest():
+			return res, fmt.Errorf("line %d: hash mismatch (entry modified)", line)
+		}
+		res.Entries, res.Head = e.Seq, e.Hash
+	}
+	if err := sc.Err(); err != nil {
+		return res, fmt.Errorf("line %d: %w", line+1, err)
+	}
+	return res, nil
+}
+
+// ErrEmpty is returned by VerifyFile for a log with no entries.
+var ErrEmpty = errors.New("audit log is empty")
+
+// VerifyFile verifies the chain stored at path.
+func VerifyFile(path string) (Result, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return Result{}, err
+	}
+	defer f.Close()
+	res, err := Verify(f)
+	if err == nil && res.Entries == 0 {
+		return res, ErrEmpty
+	}
+	return res, err
+}
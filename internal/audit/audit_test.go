@@ -0,0 +1,207 @@
+package audit
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeLog records n events to a new log and returns its path and lines.
+func writeLog(t *testing.T, n int) (string, []string) {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2026, 10, 17, 9, 0, 0, 0, time.UTC)
+	for i := range n {
+		if err := l.Record("created", "drop"+string(rune('a'+i)), "ci", start.Add(time.Duration(i)*time.Minute)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	return path, readLines(t, path)
+}
+
+func readLines(t *testing.T, path string) []string {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+}
+
+func writeLines(t *testing.T, path string, lines []string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// edit decodes line, applies fn and re-encodes it. With rehash the entry's
+// own hash is recomputed, as a forger who knows the scheme would.
+func edit(t *testing.T, line string, rehash bool, fn func(*Entry)) string {
+	t.Helper()
+	var e Entry
+	if err := json.Unmarshal([]byte(line), &e); err != nil {
+		t.Fatal(err)
+	}
+	fn(&e)
+	if rehash {
+		e.Hash = e.digest()
+	}
+	out, err := json.Marshal(e)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(out)
+}
+
+func TestRecordAndReopen(t *testing.T) {
+	path, lines := writeLog(t, 3)
+	if len(lines) != 3 {
+		t.Fatalf("%d lines, want 3", len(lines))
+	}
+	res, err := VerifyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Entries != 3 {
+		t.Errorf("%d entries, want 3", res.Entries)
+	}
+
+	// Reopening continues the chain
+	l, err := Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Record("burned", "dropa", "", time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	if err := l.Close(); err != nil {
+		t.Fatal(err)
+	}
+	next, err := VerifyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if next.Entries != 4 || next.Head == res.Head {
+		t.Errorf("after reopening: %+v (before %+v)", next, res)
+	}
+	var last Entry
+	if err := json.Unmarshal([]byte(readLines(t, path)[3]), &last); err != nil {
+		t.Fatal(err)
+	}
+	if last.Seq != 4 || last.Prev != res.Head || last.Event != "burned" || last.Key != "" {
+		t.Errorf("appended entry %+v", last)
+	}
+}
+
+func TestVerifyDetects(t *testing.T) {
+	for _, tc := range []struct {
+		name   string
+		change func(t *testing.T, lines []string) []string
+		line   string
+		errHas string
+	}{
+		{"tampered middle record", func(t *testing.T, lines []string) []string {
+			lines[1] = edit(t, lines[1], false, func(e *Entry) { e.Event = "revoked" })
+			return lines
+		}, "line 2", "entry modified"},
+		{"tampered time", func(t *testing.T, lines []string) []string {
+			lines[1] = edit(t, lines[1], false, func(e *Entry) { e.Time = e.Time.Add(time.Second) })
+			return lines
+		}, "line 2", "entry modified"},
+		{"broken link", func(t *testing.T, lines []string) []string {
+			lines[1] = edit(t, lines[1], true, func(e *Entry) { e.Prev = genesis })
+			return lines
+		}, "line 2", "chain broken"},
+		{"rehashed middle record", func(t *testing.T, lines []string) []string {
+			lines[1] = edit(t, lines[1], true, func(e *Entry) { e.Key = "someone-else" })
+			return lines
+		}, "line 3", "chain broken"},
+		{"deleted middle record", func(t *testing.T, lines []string) []string {
+			return append(lines[:1:1], lines[2:]...)
+		}, "line 2", "entry missing or reordered"},
+		{"reordered", func(t *testing.T, lines []string) []string {
+			lines[1], lines[2] = lines[2], lines[1]
+			return lines
+		}, "line 2", "entry missing or reordered"},
+		{"unknown field", func(t *testing.T, lines []string) []string {
+			lines[0] = strings.Replace(lines[0], `{"seq"`, `{"extra":1,"seq"`, 1)
+			return lines
+		}, "line 1", "invalid entry"},
+		{"garbage", func(t *testing.T, lines []string) []string {
+			return append(lines, "not json")
+		}, "line 4", "invalid entry"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			path, lines := writeLog(t, 3)
+			writeLines(t, path, tc.change(t, lines))
+			_, err := VerifyFile(path)
+			if err == nil || !strings.Contains(err.Error(), tc.line+":") || !strings.Contains(err.Error(), tc.errHas) {
+				t.Fatalf("got %v, want %s: ...%s", err, tc.line, tc.errHas)
+			}
+			// The server refuses to extend it
+			if l, err := Open(path); err == nil {
+				l.Close()
+				t.Error("Open accepted a broken chain")
+			}
+		})
+	}
+}
+
+// TestVerifyRewrittenChain documents the limit of an unkeyed chain: a
+// forger who rewrites every hash after an edit goes unnoticed except by
+// the changed head.
+func TestVerifyRewrittenChain(t *testing.T) {
+	path, lines := writeLog(t, 3)
+	before, err := VerifyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	prev := genesis
+	for i := range lines {
+		lines[i] = edit(t, lines[i], true, func(e *Entry) {
+			e.Prev = prev
+			if i == 1 {
+				e.Event = "revoked"
+			}
+		})
+		var e Entry
+		if err := json.Unmarshal([]byte(lines[i]), &e); err != nil {
+			t.Fatal(err)
+		}
+		prev = e.Hash
+	}
+	writeLines(t, path, lines)
+	after, err := VerifyFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Head == before.Head {
+		t.Error("rewritten chain kept the head hash")
+	}
+}
+
+func TestVerifyEmpty(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "audit.jsonl")
+	if err := os.WriteFile(path, []byte("\n\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	res, err := VerifyFile(path)
+	if !errors.Is(err, ErrEmpty) || res.Head != genesis {
+		t.Errorf("VerifyFile = %+v, %v; want ErrEmpty", res, err)
+	}
+	if _, err := VerifyFile(filepath.Join(t.TempDir(), "missing")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("missing file: %v", err)
+	}
+}
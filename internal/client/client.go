@@ -7,60 +7,106 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strings"
 
 	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/pow"
 )
 
 // CreateResponse is the response from POST /v1/drop.
 type CreateResponse struct {
-	ID   string `json:"id"`
-	Link string `json:"link"`
+	ID      string `json:"id"`
+	Link    string `json:"link"`
+	WebLink string `json:"web_link"` // Empty for servers without the browser page
 }
 
 // Create sends an encrypted payload to the server and returns the link.
 func Create(baseURL string, payload *crypto.EncryptedPayload) (string, error) {
+	out, err := CreateDrop(baseURL, payload)
+	if err != nil {
+		return "", err
+	}
+	return out.Link, nil
+}
+
+// CreateDrop is Create returning the full server response.
+func CreateDrop(baseURL string, payload *crypto.EncryptedPayload) (*CreateResponse, error) {
 	baseURL = strings.TrimSuffix(baseURL, "/")
 	url := baseURL + "/v1/drop"
 
 	body, err := json.Marshal(payload)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
 
 	req, err := http.NewRequest("POST", url, bytes.NewReader(body))
 	if err != nil {
-		return "", err
+		return nil, err
+	}
+	h, err := storeHeaders(baseURL)
+	if err != nil {
+		return nil, err
 	}
+	req.Header = h
 	req.Header.Set("Content-Type", "application/json")
 
 	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
-		return "", fmt.Errorf("request failed: %w", err)
+		return nil, fmt.Errorf("request failed: %w", err)
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		json.NewDecoder(resp.Body).Decode(&errResp)
-		if errResp.Error != "" {
-			return "", fmt.Errorf("server error: %s", errResp.Error)
-		}
-		return "", fmt.Errorf("server returned %d", resp.StatusCode)
+		return nil, decodeError(resp)
 	}
 
 	var out CreateResponse
 	if err := json.NewDecoder(resp.Body).Decode(&out); err != nil {
-		return "", err
+		return nil, err
 	}
-	return out.Link, nil
+	return &out, nil
+}
+
+// storeHeaders returns the headers of a request that stores ciphertext on
+// the server: the API key, if configured, and a solved anti-spam challenge,
+// if the server issues one.
+func storeHeaders(baseURL string) (http.Header, error) {
+	h := http.Header{}
+	if key := apiKey(); key != "" {
+		h.Set("Authorization", "Bearer "+key)
+	}
+	challenge, difficulty, err := fetchChallenge(baseURL)
+	if err != nil {
+		return nil, err
+	}
+	if challenge != "" {
+		h.Set(pow.HeaderChallenge, challenge)
+		h.Set(pow.HeaderNonce, pow.Solve(challenge, difficulty))
+	}
+	return h, nil
+}
+
+// fetchChallenge requests a proof-of-work challenge.
+// Returns an empty challenge when the server does not require one.
+func fetchChallenge(baseURL string) (string, int, error) {
+	var out struct {
+		Challenge  string `json:"challenge"`
+		Difficulty int    `json:"difficulty"`
+	}
+	status, err := doJSON("GET", baseURL+"/v1/challenge", nil, &out, http.StatusOK, http.StatusNotFound, http.StatusMethodNotAllowed)
+	if err != nil || status != http.StatusOK {
+		return "", 0, err
+	}
+	if out.Difficulty > pow.MaxDifficulty {
+		return "", 0, fmt.Errorf("server demands excessive proof-of-work (%d bits)", out.Difficulty)
+	}
+	return out.Challenge, out.Difficulty, nil
 }
 
 // Get fetches an encrypted payload from the server (retrieve & burn).
 func Get(link string) (*crypto.EncryptedPayload, error) {
-	req, err := http.NewRequest("GET", link, nil)
+	req, err := http.NewRequest("GET", APILink(link), nil)
 	if err != nil {
 		return nil, err
 	}
@@ -72,14 +118,7 @@ func Get(link string) (*crypto.EncryptedPayload, error) {
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		if errResp.Error != "" {
-			return nil, fmt.Errorf("server: %s", errResp.Error)
-		}
-		return nil, fmt.Errorf("server returned %d", resp.StatusCode)
+		return nil, decodeError(resp)
 	}
 
 	var p crypto.EncryptedPayload
@@ -92,9 +131,23 @@ func Get(link string) (*crypto.EncryptedPayload, error) {
 	return &p, nil
 }
 
+// APILink maps a browser link (/d/{id}) to its API link (/v1/drop/{id}),
+// so the CLI accepts either. Other links are returned unchanged.
+func APILink(link string) string {
+	u, err := url.Parse(link)
+	if err != nil {
+		return link
+	}
+	if id, ok := strings.CutPrefix(u.Path, "/d/"); ok && id != "" && !strings.Contains(id, "/") {
+		u.Path = "/v1/drop/" + id
+		return u.String()
+	}
+	return link
+}
+
 // Revoke manually destroys a secret (DELETE /v1/drop/{id}).
 func Revoke(link string) error {
-	req, err := http.NewRequest("DELETE", link, nil)
+	req, err := http.NewRequest("DELETE", APILink(link), nil)
 	if err != nil {
 		return err
 	}
@@ -104,14 +157,7 @@ func Revoke(link string) error {
 	}
 	defer resp.Body.Close()
 	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNotFound {
-		var errResp struct {
-			Error string `json:"error"`
-		}
-		_ = json.NewDecoder(resp.Body).Decode(&errResp)
-		if errResp.Error != "" {
-			return fmt.Errorf("server: %s", errResp.Error)
-		}
-		return fmt.Errorf("server returned %d", resp.StatusCode)
+		return decodeError(resp)
 	}
 	return nil
 }
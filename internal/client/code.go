@@ -0,0 +1,187 @@
+package client
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/yesahem/burnenv/internal/codes"
+	"github.com/yesahem/burnenv/internal/crypto"
+)
+
+// Short-code transfer (burnenv send / burnenv receive).
+// These calls only relay SPAKE2 messages and key-sealed blobs.
+
+// OpenCode registers the sender's SPAKE2 message and returns the channel
+// and the sender's token for the calls below. Like creating a drop, it
+// sends the API key and solves the server's proof-of-work challenge.
+func OpenCode(baseURL string, pake []byte, expiry int64) (int, string, error) {
+	var out struct {
+		Channel int    `json:"channel"`
+		Token   string `json:"token"`
+	}
+	h, err := storeHeaders(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return 0, "", err
+	}
+	body := map[string]interface{}{"pake": base64.StdEncoding.EncodeToString(pake), "expiry": expiry}
+	if _, err := doJSONWith("POST", codeURL(baseURL, "/v1/code"), h, body, &out, http.StatusCreated); err != nil {
+		return 0, "", err
+	}
+	return out.Channel, out.Token, nil
+}
+
+// CodeProgress is what the sender learns when polling a code.
+type CodeProgress struct {
+	Attempts [][]byte // Receivers' SPAKE2 messages in attempt order
+	Closed   bool     // A receiver confirmed delivery
+	ClosedBy int      // The attempt that confirmed
+	Proof    []byte   // Its key confirmation; check before trusting Closed
+}
+
+// CodeStatus returns the receivers' SPAKE2 messages and whether a receiver
+// has confirmed delivery.
+func CodeStatus(baseURL string, channel int, token string) (*CodeProgress, error) {
+	var out struct {
+		Attempts []string `json:"attempts"`
+		Closed   bool     `json:"closed"`
+		ClosedBy int      `json:"closed_by"`
+		Proof    string   `json:"proof"`
+	}
+	if _, err := doJSONWith("GET", codeURL(baseURL, fmt.Sprintf("/v1/code/%d", channel)), tokenHeader(token), nil, &out, http.StatusOK); err != nil {
+		return nil, err
+	}
+	p := &CodeProgress{Attempts: make([][]byte, len(out.Attempts)), Closed: out.Closed, ClosedBy: out.ClosedBy}
+	for i, s := range out.Attempts {
+		b, err := base64.StdEncoding.DecodeString(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid response: %w", err)
+		}
+		p.Attempts[i] = b
+	}
+	proof, err := base64.StdEncoding.DecodeString(out.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("invalid response: %w", err)
+	}
+	p.Proof = proof
+	return p, nil
+}
+
+// ClaimCode registers the receiver's SPAKE2 message and returns the attempt
+// number, the sender's message and the receiver's token. The API key and
+// proof-of-work are sent as for OpenCode.
+func ClaimCode(baseURL string, channel int, pake []byte) (int, []byte, string, error) {
+	var out struct {
+		Attempt int    `json:"attempt"`
+		Pake    string `json:"pake"`
+		Token   string `json:"token"`
+	}
+	h, err := storeHeaders(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return 0, nil, "", err
+	}
+	body := map[string]string{"pake": base64.StdEncoding.EncodeToString(pake)}
+	if _, err := doJSONWith("POST", codeURL(baseURL, fmt.Sprintf("/v1/code/%d/claim", channel)), h, body, &out, http.StatusOK); err != nil {
+		return 0, nil, "", err
+	}
+	peer, err := base64.StdEncoding.DecodeString(out.Pake)
+	if err != nil {
+		return 0, nil, "", fmt.Errorf("invalid response: %w", err)
+	}
+	return out.Attempt, peer, out.Token, nil
+}
+
+// SealCode uploads the secret sealed for one receiver attempt. token is the
+// sender's; the API key and proof-of-work are sent as for OpenCode.
+func SealCode(baseURL string, channel, attempt int, token string, sealed *crypto.SealedPayload) error {
+	h, err := storeHeaders(strings.TrimSuffix(baseURL, "/"))
+	if err != nil {
+		return err
+	}
+	h.Set(codes.HeaderToken, token)
+	_, err = doJSONWith("PUT", codeURL(baseURL, fmt.Sprintf("/v1/code/%d/claim/%d", channel, attempt)), h, sealed, nil, http.StatusOK)
+	return err
+}
+
+// FetchSealed downloads the sealed secret for an attempt.
+// Returns (nil, nil) while the sender has not uploaded it yet.
+func FetchSealed(baseURL string, channel, attempt int) (*crypto.SealedPayload, error) {
+	var out crypto.SealedPayload
+	status, err := doJSON("GET", codeURL(baseURL, fmt.Sprintf("/v1/code/%d/claim/%d", channel, attempt)), nil, &out, http.StatusOK, http.StatusAccepted)
+	if err != nil {
+		return nil, err
+	}
+	if status == http.StatusAccepted {
+		return nil, nil
+	}
+	return &out, nil
+}
+
+// CloseCode confirms delivery and burns the code. token is the receiver's
+// for attempt; proof shows the sender that the session key was agreed.
+func CloseCode(baseURL string, channel, attempt int, token string, proof []byte) error {
+	body := map[string]interface{}{"attempt": attempt, "proof": base64.StdEncoding.EncodeToString(proof)}
+	_, err := doJSONWith("DELETE", codeURL(baseURL, fmt.Sprintf("/v1/code/%d", channel)), tokenHeader(token), body, nil, http.StatusOK)
+	return err
+}
+
+func tokenHeader(token string) http.Header {
+	h := http.Header{}
+	h.Set(codes.HeaderToken, token)
+	return h
+}
+
+func codeURL(baseURL, path string) string {
+	return strings.TrimSuffix(baseURL, "/") + path
+}
+
+// doJSON sends an optional JSON body. Any status in want is accepted; the
+// response is decoded into out only for want[0]. Returns the status code.
+func doJSON(method, url string, in, out interface{}, want ...int) (int, error) {
+	return doJSONWith(method, url, nil, in, out, want...)
+}
+
+// doJSONWith is doJSON with extra request headers.
+func doJSONWith(method, url string, h http.Header, in, out interface{}, want ...int) (int, error) {
+	var body *bytes.Reader
+	if in != nil {
+		b, err := json.Marshal(in)
+		if err != nil {
+			return 0, err
+		}
+		body = bytes.NewReader(b)
+	} else {
+		body = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, url, body)
+	if err != nil {
+		return 0, err
+	}
+	for k, v := range h {
+		req.Header[k] = v
+	}
+	if in != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("request failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	for _, s := range want {
+		if resp.StatusCode != s {
+			continue
+		}
+		if out != nil && s == want[0] {
+			if err := json.NewDecoder(resp.Body).Decode(out); err != nil {
+				return s, fmt.Errorf("invalid response: %w", err)
+			}
+		}
+		return s, nil
+	}
+	return resp.StatusCode, decodeError(resp)
+}
@@ -0,0 +1,47 @@
+package client
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+)
+
+// Config is the optional client config file (see ConfigPath).
+type Config struct {
+	APIKey string `json:"api_key,omitempty"`
+}
+
+// ConfigPath returns the client config file location,
+// e.g. ~/.config/burnenv/config.json on Linux.
+func ConfigPath() string {
+	dir, err := os.UserConfigDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(dir, "burnenv", "config.json")
+}
+
+// loadConfig reads the config file; a missing or unreadable file yields
+// an empty config.
+func loadConfig() Config {
+	var cfg Config
+	path := ConfigPath()
+	if path == "" {
+		return cfg
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return cfg
+	}
+	_ = json.Unmarshal(data, &cfg)
+	return cfg
+}
+
+// apiKey returns the key used to create drops: BURNENV_API_KEY, else the
+// config file's api_key. Empty if neither is set.
+func apiKey() string {
+	if k := os.Getenv("BURNENV_API_KEY"); k != "" {
+		return k
+	}
+	return loadConfig().APIKey
+}
@@ -0,0 +1,76 @@
+package client
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/yesahem/burnenv/internal/apierr"
+)
+
+// Error is an error response from the server. Use errors.Is with the Err*
+// values below (which match on Code), or CodeOf, instead of matching text.
+type Error struct {
+	Status    int         // HTTP status
+	Code      apierr.Code // Stable error code
+	Message   string      // Human-readable; wording may change
+	RequestID string      // Quote in bug reports
+}
+
+func (e *Error) Error() string {
+	msg := e.Message
+	if msg == "" {
+		msg = string(e.Code)
+	}
+	if e.RequestID != "" {
+		return "server: " + msg + " (request ID " + e.RequestID + ")"
+	}
+	return "server: " + msg
+}
+
+// Is reports whether target is an *Error with the same code.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t.Code == e.Code
+}
+
+// Sentinels for errors.Is.
+var (
+	ErrBadRequest   = &Error{Code: apierr.BadRequest}
+	ErrTooLarge     = &Error{Code: apierr.TooLarge}
+	ErrNotFound     = &Error{Code: apierr.NotFound}
+	ErrExpired      = &Error{Code: apierr.Expired}
+	ErrExhausted    = &Error{Code: apierr.Exhausted}
+	ErrUnauthorized = &Error{Code: apierr.Unauthorized}
+	ErrForbidden    = &Error{Code: apierr.Forbidden}
+	ErrPoWFailed    = &Error{Code: apierr.PoWFailed}
+	ErrRateLimited  = &Error{Code: apierr.RateLimited}
+)
+
+// CodeOf returns the code of the *Error in err's chain, or "" if none.
+func CodeOf(err error) apierr.Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// decodeError builds an *Error from a non-success response. Servers
+// without error codes (or plain-text errors) get one derived from the status.
+func decodeError(resp *http.Response) error {
+	var body struct {
+		Error     string      `json:"error"`
+		Code      apierr.Code `json:"code"`
+		RequestID string      `json:"request_id"`
+	}
+	_ = json.NewDecoder(resp.Body).Decode(&body)
+	e := &Error{Status: resp.StatusCode, Code: body.Code, Message: body.Error, RequestID: body.RequestID}
+	if e.Code == "" {
+		e.Code = apierr.ForStatus(resp.StatusCode)
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
@@ -0,0 +1,89 @@
+package client
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/yesahem/burnenv/internal/expiry"
+)
+
+// Limits are the create limits a server accepts.
+type Limits struct {
+	MaxRequestBytes    int64 `json:"max_request_bytes"`
+	MaxCiphertextBytes int   `json:"max_ciphertext_bytes"`
+	MinExpirySeconds   int64 `json:"min_expiry_seconds"`
+	MaxExpirySeconds   int64 `json:"max_expiry_seconds"`
+	MinMaxViews        int   `json:"min_max_views"`
+	MaxMaxViews        int   `json:"max_max_views"`
+}
+
+// DefaultLimits apply to the local mock store and to servers that predate
+// GET /v1/info (the CLI's historical 2-10 minute expiry range).
+var DefaultLimits = Limits{
+	MaxRequestBytes:    2 * 1024 * 1024,
+	MaxCiphertextBytes: 2 * 1024 * 1024,
+	MinExpirySeconds:   2 * 60,
+	MaxExpirySeconds:   10 * 60,
+	MinMaxViews:        1,
+	MaxMaxViews:        100,
+}
+
+// ServerInfo is the response from GET /v1/info.
+type ServerInfo struct {
+	Version       string   `json:"version"`
+	KDFAlgorithms []string `json:"kdf_algorithms"`
+	Limits        Limits   `json:"limits"`
+	Features      []string `json:"features"`
+}
+
+// Info fetches the server's advertised policy.
+func Info(baseURL string) (*ServerInfo, error) {
+	var out ServerInfo
+	if _, err := doJSON("GET", strings.TrimSuffix(baseURL, "/")+"/v1/info", nil, &out, http.StatusOK); err != nil {
+		return nil, err
+	}
+	return &out, nil
+}
+
+// LimitsFor returns the limits of the server at baseURL, or DefaultLimits
+// for the local mock (empty baseURL) or a server without /v1/info.
+func LimitsFor(baseURL string) Limits {
+	if baseURL == "" {
+		return DefaultLimits
+	}
+	info, err := Info(baseURL)
+	if err != nil || info.Limits.MaxExpirySeconds == 0 {
+		return DefaultLimits
+	}
+	return info.Limits
+}
+
+// CheckExpiry reports whether a secret living d is within the expiry limits.
+func (l Limits) CheckExpiry(d time.Duration) error {
+	lo, hi := time.Duration(l.MinExpirySeconds)*time.Second, time.Duration(l.MaxExpirySeconds)*time.Second
+	if d < lo || d > hi {
+		return fmt.Errorf("expiry of %s is outside the allowed %s to %s", expiry.FormatDuration(d), expiry.FormatDuration(lo), expiry.FormatDuration(hi))
+	}
+	return nil
+}
+
+// ExpiryMinutesRange returns the allowed expiry in whole minutes.
+func (l Limits) ExpiryMinutesRange() (int, int) {
+	return int((l.MinExpirySeconds + 59) / 60), int(l.MaxExpirySeconds / 60)
+}
+
+// requestOverhead is room left in a create request for everything but
+// the ciphertext (salt, IV, KDF parameters, content type).
+const requestOverhead = 1024
+
+// MaxSecretBytes returns the largest plaintext whose base64 ciphertext
+// (AES-GCM adds a 16-byte tag) fits both the ciphertext and request limits.
+func (l Limits) MaxSecretBytes() int {
+	encoded := int64(l.MaxCiphertextBytes)
+	if l.MaxRequestBytes > 0 {
+		encoded = min(encoded, l.MaxRequestBytes-requestOverhead)
+	}
+	return int(max(0, encoded/4*3-16))
+}
@@ -0,0 +1,84 @@
+// Package codes provides human-friendly transfer codes such as
+// "7-crossover-clockwork" for burnenv send/receive.
+// The numeric channel is public (the server routes on it); the words are
+// the low-entropy password fed into SPAKE2 and never leave the client.
+package codes
+
+import (
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// HeaderToken carries the bearer token the server hands out when a code
+// is opened or claimed. Only its holder may poll, seal or close.
+const HeaderToken = "X-BurnEnv-Code-Token"
+
+// DefaultWords is the number of words appended after the channel.
+// Two words give 16 bits. The PAKE allows one online guess per attempt and
+// the server burns a code after 3, so an attacker wins 3 in 65536 races;
+// pass more words where that is too much.
+const DefaultWords = 2
+
+// ErrInvalidCode is returned when a code cannot be parsed.
+var ErrInvalidCode = errors.New("invalid code (expected e.g. 7-crossover-clockwork)")
+
+var wordIndex = func() map[string]struct{} {
+	m := make(map[string]struct{}, 512)
+	for _, w := range evenWords {
+		m[w] = struct{}{}
+	}
+	for _, w := range oddWords {
+		m[w] = struct{}{}
+	}
+	return m
+}()
+
+// RandomWords returns n random words joined by dashes. The words are the
+// PAKE password; they are chosen before the server assigns a channel.
+func RandomWords(n int) (string, error) {
+	if n < 1 {
+		n = DefaultWords
+	}
+	b := make([]byte, n)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate code: %w", err)
+	}
+	words := make([]string, n)
+	for i, c := range b {
+		if i%2 == 0 {
+			words[i] = evenWords[c]
+		} else {
+			words[i] = oddWords[c]
+		}
+	}
+	return strings.Join(words, "-"), nil
+}
+
+// Format joins a channel and its words into a code.
+func Format(channel int, words string) string {
+	return strconv.Itoa(channel) + "-" + words
+}
+
+// Parse splits a code into its channel and the password used for the PAKE.
+// Input is case-insensitive and tolerates spaces instead of dashes.
+func Parse(code string) (channel int, password string, err error) {
+	code = strings.ToLower(strings.TrimSpace(code))
+	code = strings.Join(strings.Fields(code), "-")
+	parts := strings.Split(code, "-")
+	if len(parts) < 2 {
+		return 0, "", ErrInvalidCode
+	}
+	channel, err = strconv.Atoi(parts[0])
+	if err != nil || channel < 1 {
+		return 0, "", ErrInvalidCode
+	}
+	for _, w := range parts[1:] {
+		if _, ok := wordIndex[w]; !ok {
+			return 0, "", fmt.Errorf("%w: unknown word %q", ErrInvalidCode, w)
+		}
+	}
+	return channel, strings.Join(parts[1:], "-"), nil
+}
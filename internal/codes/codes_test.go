@@ -0,0 +1,59 @@
+package codes
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestRandomWordsRoundTrip(t *testing.T) {
+	for _, n := range []int{0, 1, 2, 5} {
+		words, err := RandomWords(n)
+		if err != nil {
+			t.Fatal(err)
+		}
+		want := n
+		if n < 1 {
+			want = DefaultWords
+		}
+		if got := len(strings.Split(words, "-")); got != want {
+			t.Errorf("RandomWords(%d) = %q, %d words, want %d", n, words, got, want)
+		}
+		ch, pw, err := Parse(Format(42, words))
+		if err != nil || ch != 42 || pw != words {
+			t.Errorf("Parse(Format(42, %q)) = %d, %q, %v", words, ch, pw, err)
+		}
+	}
+}
+
+func TestParse(t *testing.T) {
+	even, odd := evenWords[0], oddWords[0]
+	for _, tc := range []struct {
+		code    string
+		channel int
+		words   string
+	}{
+		{"7-" + even + "-" + odd, 7, even + "-" + odd},
+		{"  7 " + strings.ToUpper(even) + "  " + odd + "\n", 7, even + "-" + odd},
+		{"123-" + odd, 123, odd},
+	} {
+		ch, words, err := Parse(tc.code)
+		if err != nil || ch != tc.channel || words != tc.words {
+			t.Errorf("Parse(%q) = %d, %q, %v; want %d, %q", tc.code, ch, words, err, tc.channel, tc.words)
+		}
+	}
+
+	for _, code := range []string{
+		"",
+		"7",
+		even + "-" + odd,
+		"0-" + even,
+		"-1-" + even,
+		"7-" + even + "-notaword",
+		"7--" + even,
+	} {
+		if _, _, err := Parse(code); !errors.Is(err, ErrInvalidCode) {
+			t.Errorf("Parse(%q): got %v, want ErrInvalidCode", code, err)
+		}
+	}
+}
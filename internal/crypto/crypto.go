@@ -26,6 +26,14 @@ const (
 	gcmNonceSize    = 12
 )
 
+// ErrDecryptionFailed is returned when authentication fails on decryption,
+// almost always because of a wrong password.
+var ErrDecryptionFailed = errors.New("decryption failed: wrong password or corrupted data")
+
+// ContentTypeTar marks a payload holding a tar archive of files
+// (burnenv create --file/--dir). Text payloads have no content type.
+const ContentTypeTar = "application/x-tar"
+
 // KDFParams holds key derivation parameters for reproducibility.
 // Stored with ciphertext so decryption can re-derive the key.
 type KDFParams struct {
@@ -44,16 +52,26 @@ type EncryptedPayload struct {
 	KDF        KDFParams `json:"kdf"`
 	Expiry     int64     `json:"expiry"`
 	MaxViews   int       `json:"max_views"`
+	// ContentType is authenticated as AES-GCM additional data, so it
+	// can't be changed without failing decryption. Empty for text.
+	ContentType string `json:"content_type,omitempty"`
 }
 
 // Encrypt encrypts plaintext with the given password.
 // Salt and IV are randomly generated per encryption.
 // Returns JSON-serializable payload safe to send to server.
 func Encrypt(plaintext []byte, password string) (*EncryptedPayload, error) {
+	return EncryptContent(plaintext, []byte(password), "")
+}
+
+// EncryptContent is Encrypt for a password held in a byte slice, which the
+// caller can wipe afterwards, and for non-text content: contentType (e.g.
+// ContentTypeTar, or "" for text) is bound to the ciphertext.
+func EncryptContent(plaintext, password []byte, contentType string) (*EncryptedPayload, error) {
 	if len(plaintext) == 0 {
 		return nil, errors.New("plaintext cannot be empty")
 	}
-	if password == "" {
+	if len(password) == 0 {
 		return nil, errors.New("password cannot be empty")
 	}
 
@@ -69,13 +87,14 @@ func Encrypt(plaintext []byte, password string) (*EncryptedPayload, error) {
 
 	// Derive key from password using Argon2id (client-side only)
 	key := argon2.IDKey(
-		[]byte(password),
+		password,
 		salt,
 		argon2Time,
 		argon2Memory,
 		argon2Threads,
 		argon2KeyLen,
 	)
+	defer clear(key)
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -87,7 +106,7 @@ func Encrypt(plaintext []byte, password string) (*EncryptedPayload, error) {
 		return nil, fmt.Errorf("gcm: %w", err)
 	}
 
-	ciphertext := gcm.Seal(nil, nonce, plaintext, nil)
+	ciphertext := gcm.Seal(nil, nonce, plaintext, additionalData(contentType))
 
 	return &EncryptedPayload{
 		Ciphertext: base64.StdEncoding.EncodeToString(ciphertext),
@@ -99,16 +118,32 @@ func Encrypt(plaintext []byte, password string) (*EncryptedPayload, error) {
 			Memory:    argon2Memory,
 			Threads:   argon2Threads,
 		},
+		ContentType: contentType,
 		// Expiry and MaxViews are set by caller after encryption
 	}, nil
 }
 
+// additionalData authenticates the content type; nil for text keeps
+// text payloads compatible with older clients.
+func additionalData(contentType string) []byte {
+	if contentType == "" {
+		return nil
+	}
+	return []byte(contentType)
+}
+
 // Decrypt decrypts an EncryptedPayload with the given password.
 func Decrypt(payload *EncryptedPayload, password string) ([]byte, error) {
+	return DecryptPassword(payload, []byte(password))
+}
+
+// DecryptPassword is Decrypt for a password held in a byte slice, which
+// the caller can wipe afterwards.
+func DecryptPassword(payload *EncryptedPayload, password []byte) ([]byte, error) {
 	if payload == nil {
 		return nil, errors.New("payload cannot be nil")
 	}
-	if password == "" {
+	if len(password) == 0 {
 		return nil, errors.New("password cannot be empty")
 	}
 
@@ -135,13 +170,14 @@ func Decrypt(payload *EncryptedPayload, password string) ([]byte, error) {
 
 	// Use KDF params from payload (allows future param evolution)
 	key := argon2.IDKey(
-		[]byte(password),
+		password,
 		salt,
 		payload.KDF.Time,
 		payload.KDF.Memory,
 		payload.KDF.Threads,
 		argon2KeyLen,
 	)
+	defer clear(key)
 
 	block, err := aes.NewCipher(key)
 	if err != nil {
@@ -153,9 +189,9 @@ func Decrypt(payload *EncryptedPayload, password string) ([]byte, error) {
 		return nil, fmt.Errorf("gcm: %w", err)
 	}
 
-	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData(payload.ContentType))
 	if err != nil {
-		return nil, errors.New("decryption failed: wrong password or corrupted data")
+		return nil, ErrDecryptionFailed
 	}
 
 	return plaintext, nil
@@ -177,3 +213,65 @@ func DeserializePayload(data []byte) (*EncryptedPayload, error) {
 	}
 	return &p, nil
 }
+
+// SealedPayload is secret data encrypted under an already-agreed key
+// (e.g. from a PAKE exchange) rather than a password.
+type SealedPayload struct {
+	IV         string `json:"iv"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// SealWithKey encrypts plaintext with a 32-byte AES-256-GCM key.
+func SealWithKey(plaintext, key []byte) (*SealedPayload, error) {
+	if len(plaintext) == 0 {
+		return nil, errors.New("plaintext cannot be empty")
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcmNonceSize)
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("failed to generate IV: %w", err)
+	}
+	return &SealedPayload{
+		IV:         base64.StdEncoding.EncodeToString(nonce),
+		Ciphertext: base64.StdEncoding.EncodeToString(gcm.Seal(nil, nonce, plaintext, nil)),
+	}, nil
+}
+
+// OpenWithKey decrypts a SealedPayload with a 32-byte AES-256-GCM key.
+func OpenWithKey(p *SealedPayload, key []byte) ([]byte, error) {
+	nonce, err := base64.StdEncoding.DecodeString(p.IV)
+	if err != nil || len(nonce) != gcmNonceSize {
+		return nil, errors.New("invalid IV")
+	}
+	ciphertext, err := base64.StdEncoding.DecodeString(p.Ciphertext)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ciphertext: %w", err)
+	}
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrDecryptionFailed
+	}
+	return plaintext, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	if len(key) != argon2KeyLen {
+		return nil, errors.New("invalid key length")
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("aes new cipher: %w", err)
+	}
+	gcm, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("gcm: %w", err)
+	}
+	return gcm, nil
+}
@@ -0,0 +1,155 @@
+// Package dotenv parses .env files: KEY=VALUE lines with optional
+// "export" prefixes, comments, single-quoted (literal) and double-quoted
+// (escaped, possibly multiline) values.
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Entry is one assignment, in file order. Lines are 1-based; EndLine
+// differs from Line for multiline quoted values.
+type Entry struct {
+	Key     string
+	Value   string
+	Line    int
+	EndLine int
+	Quoted  bool // Value was single- or double-quoted
+}
+
+// SyntaxError reports the line where parsing failed.
+type SyntaxError struct {
+	Line int
+	Msg  string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("line %d: %s", e.Line, e.Msg)
+}
+
+// Parse returns the entries of data in order. Duplicate keys are kept;
+// callers that build a map or environment let the last one win.
+func Parse(data []byte) ([]Entry, error) {
+	lines := strings.Split(strings.ReplaceAll(string(data), "\r\n", "\n"), "\n")
+	var entries []Entry
+	for i := 0; i < len(lines); i++ {
+		lineNo := i + 1
+		line := strings.TrimSpace(lines[i])
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if rest, ok := strings.CutPrefix(line, "export "); ok {
+			line = strings.TrimSpace(rest)
+		}
+		key, rest, ok := strings.Cut(line, "=")
+		if !ok {
+			return nil, &SyntaxError{lineNo, "expected KEY=VALUE"}
+		}
+		key = strings.TrimSpace(key)
+		if key == "" {
+			return nil, &SyntaxError{lineNo, "missing key before '='"}
+		}
+		if strings.ContainsAny(key, " \t") {
+			return nil, &SyntaxError{lineNo, fmt.Sprintf("key %q contains whitespace", key)}
+		}
+		raw := rest
+		rest = strings.TrimLeft(rest, " \t")
+
+		var value string
+		quoted := false
+		switch {
+		case strings.HasPrefix(rest, `"`), strings.HasPrefix(rest, "'"):
+			// Quoted values may continue over the following lines
+			quote := rest[0]
+			text := rest[1:]
+			end := -1
+			for {
+				if end = closingQuote(text, quote); end >= 0 {
+					break
+				}
+				if i+1 >= len(lines) {
+					return nil, &SyntaxError{lineNo, fmt.Sprintf("unterminated %c-quoted value for %s", quote, key)}
+				}
+				i++
+				text += "\n" + lines[i]
+			}
+			if tail := strings.TrimSpace(text[end+1:]); tail != "" && !strings.HasPrefix(tail, "#") {
+				return nil, &SyntaxError{i + 1, fmt.Sprintf("unexpected %q after quoted value for %s", tail, key)}
+			}
+			value, quoted = text[:end], true
+			if quote == '"' {
+				value = unescape(value)
+			}
+		case strings.HasPrefix(rest, "#") && len(rest) < len(raw):
+			// "KEY= # comment" is empty
+		default:
+			value = stripComment(rest)
+		}
+		entries = append(entries, Entry{Key: key, Value: value, Line: lineNo, EndLine: i + 1, Quoted: quoted})
+	}
+	return entries, nil
+}
+
+// Environ returns entries as KEY=VALUE strings for os/exec.
+func Environ(entries []Entry) []string {
+	env := make([]string, len(entries))
+	for i, e := range entries {
+		env[i] = e.Key + "=" + e.Value
+	}
+	return env
+}
+
+// closingQuote returns the index of the quote ending s, or -1. Backslash
+// escapes apply only inside double quotes.
+func closingQuote(s string, quote byte) int {
+	for i := 0; i < len(s); i++ {
+		switch {
+		case s[i] == '\\' && quote == '"':
+			i++
+		case s[i] == quote:
+			return i
+		}
+	}
+	return -1
+}
+
+// unescape expands the escapes allowed in double-quoted values.
+func unescape(s string) string {
+	if !strings.Contains(s, `\`) {
+		return s
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if s[i] != '\\' || i+1 == len(s) {
+			b.WriteByte(s[i])
+			continue
+		}
+		i++
+		switch s[i] {
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 't':
+			b.WriteByte('\t')
+		case '"', '\\', '$', '\'':
+			b.WriteByte(s[i])
+		default:
+			b.WriteByte('\\')
+			b.WriteByte(s[i])
+		}
+	}
+	return b.String()
+}
+
+// stripComment trims an unquoted value and drops a " #" comment.
+func stripComment(s string) string {
+	for i := 1; i < len(s); i++ {
+		if s[i] == '#' && (s[i-1] == ' ' || s[i-1] == '\t') {
+			s = s[:i]
+			break
+		}
+	}
+	return strings.TrimSpace(s)
+}
@@ -0,0 +1,94 @@
+package dotenv
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want []Entry
+	}{
+		{"plain", "A=1\nB=two words\n", []Entry{
+			{Key: "A", Value: "1", Line: 1, EndLine: 1},
+			{Key: "B", Value: "two words", Line: 2, EndLine: 2},
+		}},
+		{"comments and blanks", "# top\n\n  A=1 # trailing\nB=a#b\nC= # only a comment\nD=\n", []Entry{
+			{Key: "A", Value: "1", Line: 3, EndLine: 3},
+			{Key: "B", Value: "a#b", Line: 4, EndLine: 4},
+			{Key: "C", Value: "", Line: 5, EndLine: 5},
+			{Key: "D", Value: "", Line: 6, EndLine: 6},
+		}},
+		{"export and spaces", "export A=1\n  export   B = 2 \nexporter=3\n", []Entry{
+			{Key: "A", Value: "1", Line: 1, EndLine: 1},
+			{Key: "B", Value: "2", Line: 2, EndLine: 2},
+			{Key: "exporter", Value: "3", Line: 3, EndLine: 3},
+		}},
+		{"equals in value", "URL=postgres://u:p@h/db?ssl=true\n", []Entry{
+			{Key: "URL", Value: "postgres://u:p@h/db?ssl=true", Line: 1, EndLine: 1},
+		}},
+		{"single quotes are literal", `A='$HOME \n "x" # y'` + "\n", []Entry{
+			{Key: "A", Value: `$HOME \n "x" # y`, Line: 1, EndLine: 1, Quoted: true},
+		}},
+		{"double quote escapes", `A="tab\tnl\ncr\rq\"b\\d\$s\'u\z"` + "\n", []Entry{
+			{Key: "A", Value: "tab\tnl\ncr\rq\"b\\d$s'u\\z", Line: 1, EndLine: 1, Quoted: true},
+		}},
+		{"comment after quotes", `A="x # y" # z` + "\nB='' # empty\n", []Entry{
+			{Key: "A", Value: "x # y", Line: 1, EndLine: 1, Quoted: true},
+			{Key: "B", Value: "", Line: 2, EndLine: 2, Quoted: true},
+		}},
+		{"multiline", "A=\"one\ntwo\n\nfour\"\nB='x\ny'\nC=3\n", []Entry{
+			{Key: "A", Value: "one\ntwo\n\nfour", Line: 1, EndLine: 4, Quoted: true},
+			{Key: "B", Value: "x\ny", Line: 5, EndLine: 6, Quoted: true},
+			{Key: "C", Value: "3", Line: 7, EndLine: 7},
+		}},
+		{"CRLF", "A=1\r\nB=\"x\r\ny\"\r\n", []Entry{
+			{Key: "A", Value: "1", Line: 1, EndLine: 1},
+			{Key: "B", Value: "x\ny", Line: 2, EndLine: 3, Quoted: true},
+		}},
+		{"duplicates kept", "A=1\nA=2\n", []Entry{
+			{Key: "A", Value: "1", Line: 1, EndLine: 1},
+			{Key: "A", Value: "2", Line: 2, EndLine: 2},
+		}},
+		{"empty", "", nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Parse([]byte(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Parse(%q) =\n%+v\nwant\n%+v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		in   string
+		line int
+	}{
+		{"A=1\nnot an assignment\n", 2},
+		{"=value\n", 1},
+		{"MY KEY=1\n", 1},
+		{"A=1\nB=\"never closed\nC=3\n", 2},
+		{"A='never closed\n", 1},
+		{`A="escaped quote\"` + "\n", 1},
+		{"A=\"x\ny\" trailing\n", 2},
+		{"A='x' y\n", 1},
+	} {
+		_, err := Parse([]byte(tc.in))
+		var se *SyntaxError
+		if !errors.As(err, &se) {
+			t.Errorf("Parse(%q): got %v, want a SyntaxError", tc.in, err)
+			continue
+		}
+		if se.Line != tc.line {
+			t.Errorf("Parse(%q): error on line %d, want %d (%v)", tc.in, se.Line, tc.line, err)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+package dotenv
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Output formats accepted by Render.
+const (
+	FormatDotenv    = "dotenv"     // KEY=VALUE, double-quoted where needed
+	FormatJSON      = "json"       // {"KEY": "VALUE"}
+	FormatYAML      = "yaml"       // KEY: VALUE
+	FormatExport    = "export"     // export KEY='VALUE' for POSIX shells
+	FormatDockerEnv = "docker-env" // docker run --env-file (no quoting, single line)
+	FormatK8sSecret = "k8s-secret" // Kubernetes Secret manifest with base64 data
+)
+
+// Formats lists the supported output formats.
+var Formats = []string{FormatDotenv, FormatJSON, FormatYAML, FormatExport, FormatDockerEnv, FormatK8sSecret}
+
+var (
+	shellName   = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+	k8sKey      = regexp.MustCompile(`^[-._a-zA-Z0-9]+$`)
+	plainDotenv = regexp.MustCompile(`^[A-Za-z0-9_./:@%+,=-]*$`)
+)
+
+// Unique returns entries with one per key: the last assignment wins,
+// kept at the position of the first.
+func Unique(entries []Entry) []Entry {
+	index := make(map[string]int, len(entries))
+	var out []Entry
+	for _, e := range entries {
+		if i, ok := index[e.Key]; ok {
+			out[i].Value = e.Value
+			continue
+		}
+		index[e.Key] = len(out)
+		out = append(out, e)
+	}
+	return out
+}
+
+// Render returns entries encoded in format. name is the metadata.name of a
+// k8s-secret manifest and is ignored otherwise. Keys or values the
+// target cannot represent are errors naming the source line.
+func Render(entries []Entry, format, name string) ([]byte, error) {
+	entries = Unique(entries)
+	var buf bytes.Buffer
+	switch format {
+	case FormatDotenv:
+		for _, e := range entries {
+			fmt.Fprintf(&buf, "%s=%s\n", e.Key, quoteDotenv(e.Value))
+		}
+	case FormatExport:
+		for _, e := range entries {
+			if !shellName.MatchString(e.Key) {
+				return nil, &SyntaxError{e.Line, fmt.Sprintf("%q is not a valid shell variable name", e.Key)}
+			}
+			fmt.Fprintf(&buf, "export %s=%s\n", e.Key, quoteShell(e.Value))
+		}
+	case FormatDockerEnv:
+		for _, e := range entries {
+			// Docker takes everything after '=' literally, up to the newline
+			if strings.ContainsAny(e.Value, "\r\n") {
+				return nil, &SyntaxError{e.Line, fmt.Sprintf("docker env files cannot hold the multiline value of %s", e.Key)}
+			}
+			fmt.Fprintf(&buf, "%s=%s\n", e.Key, e.Value)
+		}
+	case FormatJSON:
+		buf.WriteString("{")
+		for i, e := range entries {
+			if i > 0 {
+				buf.WriteString(",")
+			}
+			fmt.Fprintf(&buf, "\n  %s: %s", jsonString(e.Key), jsonString(e.Value))
+		}
+		if len(entries) > 0 {
+			buf.WriteString("\n")
+		}
+		buf.WriteString("}\n")
+	case FormatYAML:
+		m := mapping()
+		for _, e := range entries {
+			addPair(m, e.Key, e.Value)
+		}
+		if err := encodeYAML(&buf, m); err != nil {
+			return nil, err
+		}
+	case FormatK8sSecret:
+		data := mapping()
+		for _, e := range entries {
+			if !k8sKey.MatchString(e.Key) {
+				return nil, &SyntaxError{e.Line, fmt.Sprintf("%q is not a valid Kubernetes Secret key", e.Key)}
+			}
+			addPair(data, e.Key, base64.StdEncoding.EncodeToString([]byte(e.Value)))
+		}
+		meta := mapping()
+		addPair(meta, "name", name)
+		doc := mapping()
+		addPair(doc, "apiVersion", "v1")
+		addPair(doc, "kind", "Secret")
+		doc.Content = append(doc.Content, scalar("metadata"), meta)
+		addPair(doc, "type", "Opaque")
+		doc.Content = append(doc.Content, scalar("data"), data)
+		if err := encodeYAML(&buf, doc); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown format %q (use %s)", format, strings.Join(Formats, ", "))
+	}
+	return buf.Bytes(), nil
+}
+
+// quoteDotenv leaves simple values bare and double-quotes the rest with
+// the escapes Parse understands.
+func quoteDotenv(v string) string {
+	if plainDotenv.MatchString(v) {
+		return v
+	}
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`, "$", `\$`)
+	return `"` + r.Replace(v) + `"`
+}
+
+// quoteShell single-quotes v; nothing inside single quotes is special to
+// a POSIX shell except the quote itself.
+func quoteShell(v string) string {
+	return "'" + strings.ReplaceAll(v, "'", `'\''`) + "'"
+}
+
+// jsonString quotes s without HTML escaping, so "<" stays readable.
+func jsonString(s string) string {
+	var b strings.Builder
+	enc := json.NewEncoder(&b)
+	enc.SetEscapeHTML(false)
+	enc.Encode(s)
+	return strings.TrimSuffix(b.String(), "\n")
+}
+
+func mapping() *yaml.Node { return &yaml.Node{Kind: yaml.MappingNode} }
+
+// scalar is always a string, so the encoder quotes values such as "true"
+// or "0123" that YAML would otherwise read as another type.
+func scalar(s string) *yaml.Node {
+	return &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: s}
+}
+
+func addPair(m *yaml.Node, k, v string) {
+	m.Content = append(m.Content, scalar(k), scalar(v))
+}
+
+func encodeYAML(buf *bytes.Buffer, n *yaml.Node) error {
+	enc := yaml.NewEncoder(buf)
+	enc.SetIndent(2)
+	if err := enc.Encode(n); err != nil {
+		return err
+	}
+	return enc.Close()
+}
@@ -0,0 +1,246 @@
+package dotenv
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"os/exec"
+	"strings"
+	"testing"
+
+	"gopkg.in/yaml.v3"
+)
+
+// awkward holds values each format has to quote or escape.
+var awkward = []Entry{
+	{Key: "PLAIN", Value: "postgres://u@db:5432/app"},
+	{Key: "EMPTY", Value: ""},
+	{Key: "DOLLAR", Value: "pa$$word ${HOME} $(id)"},
+	{Key: "DOUBLE", Value: `say "hi"`},
+	{Key: "SINGLE", Value: "it's"},
+	{Key: "QUOTES", Value: `'"'"`},
+	{Key: "HASH", Value: "a#b # not a comment"},
+	{Key: "LEADING_HASH", Value: "#fff"},
+	{Key: "BACKSLASH", Value: `C:\new\tab\\`},
+	{Key: "SPACES", Value: "  padded  "},
+	{Key: "TAB", Value: "a\tb"},
+	{Key: "MULTILINE", Value: "-----BEGIN KEY-----\nabc\n-----END KEY-----\n"},
+	{Key: "CRLF", Value: "one\r\ntwo"},
+	{Key: "BOOL", Value: "true"},
+	{Key: "OCTAL", Value: "0123"},
+	{Key: "UNICODE", Value: "héllo <wörld> & ✓"},
+}
+
+// values maps the keys of entries to their values.
+func values(entries []Entry) map[string]string {
+	m := make(map[string]string, len(entries))
+	for _, e := range Unique(entries) {
+		m[e.Key] = e.Value
+	}
+	return m
+}
+
+func checkValues(t *testing.T, format string, got map[string]string, want []Entry) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Errorf("%s: %d keys, want %d", format, len(got), len(want))
+	}
+	for _, e := range want {
+		if v, ok := got[e.Key]; !ok || v != e.Value {
+			t.Errorf("%s: %s = %q, want %q", format, e.Key, v, e.Value)
+		}
+	}
+}
+
+func TestRenderDotenvRoundTrip(t *testing.T) {
+	out, err := Render(awkward, FormatDotenv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entries, err := Parse(out)
+	if err != nil {
+		t.Fatalf("Parse(Render(dotenv)): %v\n%s", err, out)
+	}
+	checkValues(t, FormatDotenv, values(entries), awkward)
+
+	// One line per entry, bare only where nothing needs quoting
+	if lines := strings.Count(string(out), "\n"); lines != len(awkward) {
+		t.Errorf("%d lines for %d entries:\n%s", lines, len(awkward), out)
+	}
+	for _, line := range []string{"PLAIN=postgres://u@db:5432/app\n", "EMPTY=\n", `DOLLAR="pa\$\$word \${HOME} \$(id)"` + "\n"} {
+		if !strings.Contains(string(out), line) {
+			t.Errorf("output lacks %q:\n%s", line, out)
+		}
+	}
+
+	// Rendering what was parsed gives the same file again
+	again, err := Render(entries, FormatDotenv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(again) != string(out) {
+		t.Errorf("second render differs:\n%s\nwant\n%s", again, out)
+	}
+}
+
+func TestRenderExport(t *testing.T) {
+	out, err := Render([]Entry{{Key: "A", Value: "it's"}, {Key: "B", Value: "''"}, {Key: "C", Value: "$HOME `id`"}}, FormatExport, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "export A='it'\\''s'\nexport B=''\\'''\\'''\nexport C='$HOME `id`'\n"
+	if string(out) != want {
+		t.Errorf("export =\n%s\nwant\n%s", out, want)
+	}
+
+	// A POSIX shell reads back exactly the original values
+	sh, err := exec.LookPath("sh")
+	if err != nil {
+		t.Skip("no sh:", err)
+	}
+	out, err = Render(awkward, FormatExport, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	script := string(out)
+	for _, e := range awkward {
+		script += `printf '%s\0' "$` + e.Key + "\"\n"
+	}
+	printed, err := exec.Command(sh, "-c", script).Output()
+	if err != nil {
+		t.Fatalf("sh: %v", err)
+	}
+	got := strings.Split(string(printed), "\x00")
+	for i, e := range awkward {
+		if i >= len(got) || got[i] != e.Value {
+			t.Errorf("sh: %s = %q, want %q", e.Key, got[min(i, len(got)-1)], e.Value)
+		}
+	}
+}
+
+func TestRenderJSON(t *testing.T) {
+	out, err := Render(awkward, FormatJSON, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got map[string]string
+	if err := json.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	checkValues(t, FormatJSON, got, awkward)
+	if !strings.Contains(string(out), "<wörld> & ✓") {
+		t.Errorf("JSON escapes HTML:\n%s", out)
+	}
+	if out, _ := Render(nil, FormatJSON, ""); string(out) != "{}\n" {
+		t.Errorf("empty JSON = %q", out)
+	}
+}
+
+func TestRenderYAML(t *testing.T) {
+	entries := append([]Entry{
+		{Key: "NULL", Value: "null"},
+		{Key: "TILDE", Value: "~"},
+		{Key: "YES", Value: "yes"},
+		{Key: "FLOAT", Value: "1e3"},
+		{Key: "COLON", Value: "a: b"},
+		{Key: "true", Value: "key that YAML would read as a bool"},
+	}, awkward...)
+	out, err := Render(entries, FormatYAML, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Decoding into interface values shows every scalar stayed a string
+	var got map[string]any
+	if err := yaml.Unmarshal(out, &got); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	strs := make(map[string]string, len(got))
+	for k, v := range got {
+		s, ok := v.(string)
+		if !ok {
+			t.Errorf("%s decoded as %T %v, want a string", k, v, v)
+		}
+		strs[k] = s
+	}
+	checkValues(t, FormatYAML, strs, entries)
+	for _, line := range []string{`BOOL: "true"`, `OCTAL: "0123"`} {
+		if !strings.Contains(string(out), line+"\n") {
+			t.Errorf("output lacks %q:\n%s", line, out)
+		}
+	}
+}
+
+func TestRenderDockerEnv(t *testing.T) {
+	out, err := Render([]Entry{{Key: "A", Value: `"quoted" $kept # too`}, {Key: "B", Value: ""}}, FormatDockerEnv, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := "A=\"quoted\" $kept # too\nB=\n"; string(out) != want {
+		t.Errorf("docker-env = %q, want %q", out, want)
+	}
+
+	for _, v := range []string{"one\ntwo", "one\rtwo", "trailing\n"} {
+		_, err := Render([]Entry{{Key: "OK", Value: "1", Line: 1}, {Key: "CERT", Value: v, Line: 2}}, FormatDockerEnv, "")
+		var se *SyntaxError
+		if !errors.As(err, &se) || se.Line != 2 || !strings.Contains(se.Msg, "CERT") {
+			t.Errorf("multiline %q: got %v, want a line 2 error naming CERT", v, err)
+		}
+	}
+}
+
+func TestRenderK8sSecret(t *testing.T) {
+	entries := []Entry{{Key: "API_KEY", Value: "s3cr3t"}, {Key: "tls.crt", Value: "-----BEGIN-----\n"}, {Key: "my-key_2", Value: ""}}
+	out, err := Render(entries, FormatK8sSecret, "app-env")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		APIVersion string `yaml:"apiVersion"`
+		Kind       string
+		Metadata   struct{ Name string }
+		Type       string
+		Data       map[string]string
+	}
+	if err := yaml.Unmarshal(out, &doc); err != nil {
+		t.Fatalf("%v\n%s", err, out)
+	}
+	if doc.APIVersion != "v1" || doc.Kind != "Secret" || doc.Metadata.Name != "app-env" || doc.Type != "Opaque" {
+		t.Errorf("manifest header: %+v", doc)
+	}
+	got := make(map[string]string)
+	for k, v := range doc.Data {
+		raw, err := base64.StdEncoding.DecodeString(v)
+		if err != nil {
+			t.Errorf("data %s: %v", k, err)
+		}
+		got[k] = string(raw)
+	}
+	checkValues(t, FormatK8sSecret, got, entries)
+
+	for _, key := range []string{"MY KEY", "a/b", "café", "x=y"} {
+		_, err := Render([]Entry{{Key: key, Value: "v", Line: 3}}, FormatK8sSecret, "app-env")
+		var se *SyntaxError
+		if !errors.As(err, &se) || se.Line != 3 {
+			t.Errorf("key %q: got %v, want a line 3 error", key, err)
+		}
+	}
+}
+
+func TestRenderInvalid(t *testing.T) {
+	if _, err := Render(awkward, "toml", ""); err == nil || !strings.Contains(err.Error(), "unknown format") {
+		t.Errorf("unknown format: %v", err)
+	}
+	_, err := Render([]Entry{{Key: "my-var", Value: "1", Line: 4}}, FormatExport, "")
+	var se *SyntaxError
+	if !errors.As(err, &se) || se.Line != 4 {
+		t.Errorf("export of my-var: got %v, want a line 4 error", err)
+	}
+}
+
+func TestUnique(t *testing.T) {
+	got := Unique(mustParse(t, "A=1\nB=2\nA=3\nC=4\nB=5\n"))
+	want := []string{"A=3", "B=5", "C=4"}
+	if env := Environ(got); strings.Join(env, " ") != strings.Join(want, " ") {
+		t.Errorf("Unique = %v, want %v", env, want)
+	}
+}
@@ -0,0 +1,50 @@
+package dotenv
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Warning is a likely mistake in otherwise parseable content.
+type Warning struct {
+	Line int
+	Key  string
+	Msg  string
+}
+
+func (w Warning) String() string {
+	return fmt.Sprintf("line %d: %s", w.Line, w.Msg)
+}
+
+// Lint parses data and reports duplicate keys, empty values, keys that
+// are not portable variable names and unquoted values with whitespace
+// (which a shell sourcing the file would split). A syntax error is
+// returned as the error.
+func Lint(data []byte) ([]Warning, error) {
+	entries, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	var warnings []Warning
+	first := make(map[string]int, len(entries))
+	for _, e := range entries {
+		warn := func(format string, args ...any) {
+			warnings = append(warnings, Warning{e.Line, e.Key, fmt.Sprintf(format, args...)})
+		}
+		if line, ok := first[e.Key]; ok {
+			warn("%s is already set on line %d; the last value wins", e.Key, line)
+		} else {
+			first[e.Key] = e.Line
+		}
+		if !shellName.MatchString(e.Key) {
+			warn("%q is not a valid variable name (use letters, digits and _, not starting with a digit)", e.Key)
+		}
+		switch {
+		case e.Value == "":
+			warn("%s has an empty value", e.Key)
+		case !e.Quoted && strings.ContainsAny(e.Value, " \t"):
+			warn("unquoted value of %s contains whitespace; wrap it in quotes", e.Key)
+		}
+	}
+	return warnings, nil
+}
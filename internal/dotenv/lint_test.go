@@ -0,0 +1,68 @@
+package dotenv
+
+import (
+	"errors"
+	"slices"
+	"testing"
+)
+
+func TestLint(t *testing.T) {
+	for _, tc := range []struct {
+		name string
+		in   string
+		want []Warning
+	}{
+		{"clean", "# comment\nexport A=1\nB=\"two words\"\nC='x y'\n_D2=4\n", nil},
+		{"duplicate", "A=1\nB=2\nA=3\nA=4\n", []Warning{
+			{3, "A", "A is already set on line 1; the last value wins"},
+			{4, "A", "A is already set on line 1; the last value wins"},
+		}},
+		{"empty values", "A=\nB=\"\"\nC= # comment\n", []Warning{
+			{1, "A", "A has an empty value"},
+			{2, "B", "B has an empty value"},
+			{3, "C", "C has an empty value"},
+		}},
+		{"invalid keys", "1A=1\nmy-key=2\nb.c=3\n", []Warning{
+			{1, "1A", `"1A" is not a valid variable name (use letters, digits and _, not starting with a digit)`},
+			{2, "my-key", `"my-key" is not a valid variable name (use letters, digits and _, not starting with a digit)`},
+			{3, "b.c", `"b.c" is not a valid variable name (use letters, digits and _, not starting with a digit)`},
+		}},
+		{"unquoted whitespace", "A=two words\nB=a\tb # comment\nC=  trimmed  \n", []Warning{
+			{1, "A", "unquoted value of A contains whitespace; wrap it in quotes"},
+			{2, "B", "unquoted value of B contains whitespace; wrap it in quotes"},
+		}},
+		{"line of a multiline value", "A=\"x\ny\"\nA=\n", []Warning{
+			{3, "A", "A is already set on line 1; the last value wins"},
+			{3, "A", "A has an empty value"},
+		}},
+		{"several on one line", "X=1\nX=\n", []Warning{
+			{2, "X", "X is already set on line 1; the last value wins"},
+			{2, "X", "X has an empty value"},
+		}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Lint([]byte(tc.in))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if !slices.Equal(got, tc.want) {
+				t.Errorf("Lint(%q) =\n%v\nwant\n%v", tc.in, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestLintSyntaxError(t *testing.T) {
+	warnings, err := Lint([]byte("A=1\nB='open\n"))
+	var se *SyntaxError
+	if !errors.As(err, &se) || se.Line != 2 || warnings != nil {
+		t.Errorf("Lint = %v, %v; want a line 2 SyntaxError", warnings, err)
+	}
+}
+
+func TestWarningString(t *testing.T) {
+	w := Warning{Line: 7, Key: "A", Msg: "A has an empty value"}
+	if got, want := w.String(), "line 7: A has an empty value"; got != want {
+		t.Errorf("String() = %q, want %q", got, want)
+	}
+}
@@ -0,0 +1,163 @@
+package dotenv
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ChangeKind classifies an incoming key against an existing file.
+type ChangeKind int
+
+const (
+	Added     ChangeKind = iota // Not in the file yet
+	Changed                     // In the file with a different value
+	Unchanged                   // In the file with the same value
+)
+
+// Change is one incoming key and how merging it would affect the file.
+type Change struct {
+	Key  string
+	Kind ChangeKind
+}
+
+// Conflict policies for keys whose value differs from the file's.
+const (
+	ConflictKeep    = "keep"    // Leave the file's value
+	ConflictReplace = "replace" // Take the incoming value
+	ConflictFail    = "fail"    // Refuse to merge
+)
+
+// ConflictPolicies lists the accepted conflict policies.
+var ConflictPolicies = []string{ConflictKeep, ConflictReplace, ConflictFail}
+
+// Diff reports each incoming key, in order, against existing. On both
+// sides the last assignment of a key is the effective one.
+func Diff(existing, incoming []Entry) []Change {
+	current := make(map[string]string, len(existing))
+	for _, e := range existing {
+		current[e.Key] = e.Value
+	}
+	var changes []Change
+	for _, e := range Unique(incoming) {
+		kind := Added
+		if v, ok := current[e.Key]; ok {
+			kind = Unchanged
+			if v != e.Value {
+				kind = Changed
+			}
+		}
+		changes = append(changes, Change{Key: e.Key, Kind: kind})
+	}
+	return changes
+}
+
+// Merge returns data, a .env file, with incoming added at the end and,
+// when replace is set, changed keys rewritten in place. Comments, order
+// and formatting of untouched lines are preserved.
+func Merge(data []byte, incoming []Entry, replace bool) ([]byte, error) {
+	existing, err := Parse(data)
+	if err != nil {
+		return nil, err
+	}
+	eol := "\n"
+	if strings.Contains(string(data), "\r\n") {
+		eol = "\r\n"
+	}
+	lines := strings.Split(string(data), eol)
+
+	values := make(map[string]string)
+	for _, e := range Unique(incoming) {
+		values[e.Key] = e.Value
+	}
+
+	// Every assignment of a replaced key is rewritten, so duplicates agree
+	rewrite := make(map[int]Entry) // 0-based first line -> entry
+	present := make(map[string]bool)
+	for _, e := range existing {
+		present[e.Key] = true
+		if v, ok := values[e.Key]; ok && replace && v != e.Value {
+			rewrite[e.Line-1] = e
+		}
+	}
+
+	var b strings.Builder
+	for i := 0; i < len(lines); i++ {
+		e, ok := rewrite[i]
+		if !ok {
+			b.WriteString(lines[i])
+		} else {
+			if strings.HasPrefix(strings.TrimSpace(lines[i]), "export ") {
+				b.WriteString("export ")
+			}
+			b.WriteString(e.Key + "=" + quoteDotenv(values[e.Key]))
+			i = e.EndLine - 1
+		}
+		if i < len(lines)-1 {
+			b.WriteString(eol)
+		}
+	}
+
+	out := b.String()
+	for _, e := range Unique(incoming) {
+		if present[e.Key] {
+			continue
+		}
+		if out != "" && !strings.HasSuffix(out, eol) {
+			out += eol
+		}
+		out += e.Key + "=" + quoteDotenv(e.Value) + eol
+	}
+	return []byte(out), nil
+}
+
+// WriteFile atomically replaces path with data: it is written to a
+// temporary file in the same directory and renamed over path. An existing
+// file is first copied to path+".bak" (returned as backup) the same way,
+// so a stale or symlinked .bak is replaced rather than written through.
+// Both keep the existing file's mode; new files are created 0600.
+func WriteFile(path string, data []byte) (backup string, err error) {
+	mode := fs.FileMode(0o600)
+	old, err := os.ReadFile(path)
+	switch {
+	case err == nil:
+		if info, err := os.Stat(path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		backup = path + ".bak"
+		if err := writeAtomic(backup, old, mode); err != nil {
+			return "", err
+		}
+	case !errors.Is(err, fs.ErrNotExist):
+		return "", err
+	}
+	return backup, writeAtomic(path, data, mode)
+}
+
+// writeAtomic writes data to a temporary file with mode next to path and
+// renames it over path.
+func writeAtomic(path string, data []byte, mode fs.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), path)
+}
@@ -0,0 +1,190 @@
+package dotenv
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"testing"
+)
+
+func mustParse(t *testing.T, s string) []Entry {
+	t.Helper()
+	entries, err := Parse([]byte(s))
+	if err != nil {
+		t.Fatalf("Parse(%q): %v", s, err)
+	}
+	return entries
+}
+
+func TestDiff(t *testing.T) {
+	existing := mustParse(t, "A=1\nB=2\nB=3\nC=x\n")
+	incoming := mustParse(t, "A=1\nB=2\nD=4\nC=y\nD=5\n")
+	want := []Change{{"A", Unchanged}, {"B", Changed}, {"D", Added}, {"C", Changed}}
+	if got := Diff(existing, incoming); !slices.Equal(got, want) {
+		t.Errorf("Diff = %v, want %v", got, want)
+	}
+}
+
+func TestMerge(t *testing.T) {
+	for _, tc := range []struct {
+		name     string
+		file     string
+		incoming string
+		replace  bool
+		want     string
+	}{
+		{"keep", "# db\nA=1\nB=old # note\n", "B=new\nC=3", false,
+			"# db\nA=1\nB=old # note\nC=3\n"},
+		{"replace", "# db\nA=1\nB=old # note\n", "B=new\nC=3", true,
+			"# db\nA=1\nB=new\nC=3\n"},
+		{"replace keeps export", "export B=old\n", "B=new", true,
+			"export B=new\n"},
+		{"replace every duplicate", "B=1\nX=0\nB=2\n", "B=3", true,
+			"B=3\nX=0\nB=3\n"},
+		{"replace multiline", "A=\"one\ntwo\"\nZ=1\n", "A=flat", true,
+			"A=flat\nZ=1\n"},
+		{"quotes new values", "A=1\n", "B=has space\nC=a\"b", false,
+			"A=1\nB=\"has space\"\nC=\"a\\\"b\"\n"},
+		{"no trailing newline", "A=1", "B=2", false,
+			"A=1\nB=2\n"},
+		{"CRLF", "A=1\r\nB=old\r\n", "B=new\nC=3", true,
+			"A=1\r\nB=new\r\nC=3\r\n"},
+		{"empty file", "", "A=1", false,
+			"A=1\n"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Merge([]byte(tc.file), mustParse(t, tc.incoming), tc.replace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tc.want {
+				t.Errorf("Merge =\n%q\nwant\n%q", got, tc.want)
+			}
+			// The merge result is itself valid and holds the incoming values
+			merged := mustParse(t, string(got))
+			for _, c := range Diff(merged, mustParse(t, tc.incoming)) {
+				if c.Kind == Added || (tc.replace && c.Kind == Changed) {
+					t.Errorf("key %s is %v after merging", c.Key, c.Kind)
+				}
+			}
+		})
+	}
+}
+
+// TestMergeNoop checks a merge with nothing new leaves the file byte for byte.
+func TestMergeNoop(t *testing.T) {
+	for _, file := range []string{
+		"A=1\nB=2\n",
+		"# comment\r\nA=1\r\nexport B='2'\r\n",
+		"A=1\n\n\nB=\"2\"",
+	} {
+		for _, replace := range []bool{false, true} {
+			got, err := Merge([]byte(file), mustParse(t, "B=2\nA=1"), replace)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != file {
+				t.Errorf("Merge(%q, replace=%v) = %q, want it unchanged", file, replace, got)
+			}
+		}
+	}
+}
+
+func TestMergeInvalidFile(t *testing.T) {
+	if _, err := Merge([]byte("A=\"unterminated\n"), nil, false); err == nil {
+		t.Error("invalid file merged")
+	}
+}
+
+func TestWriteFile(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+
+	// New file: 0600, no backup
+	backup, err := WriteFile(path, []byte("A=1\n"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if backup != "" {
+		t.Errorf("backup %q for a new file", backup)
+	}
+	checkFile(t, path, "A=1\n", 0o600)
+
+	// Existing file: mode kept, old content backed up with the same mode
+	if err := os.Chmod(path, 0o640); err != nil {
+		t.Fatal(err)
+	}
+	if backup, err = WriteFile(path, []byte("A=2\n")); err != nil {
+		t.Fatal(err)
+	}
+	if backup != path+".bak" {
+		t.Errorf("backup %q, want %q", backup, path+".bak")
+	}
+	checkFile(t, path, "A=2\n", 0o640)
+	checkFile(t, backup, "A=1\n", 0o640)
+
+	// A stale world-readable backup takes the file's mode
+	if err := os.Chmod(backup, 0o666); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := WriteFile(path, []byte("A=3\n")); err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, backup, "A=2\n", 0o640)
+
+	leftovers, _ := filepath.Glob(filepath.Join(dir, ".*.tmp"))
+	if len(leftovers) > 0 {
+		t.Errorf("temporary files left: %v", leftovers)
+	}
+}
+
+// TestWriteFileBackupSymlink checks a symlinked .env.bak is replaced, not
+// written through.
+func TestWriteFileBackupSymlink(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, ".env")
+	target := filepath.Join(dir, "elsewhere")
+	if err := os.WriteFile(path, []byte("SECRET=1\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(target, []byte("untouched"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, path+".bak"); err != nil {
+		t.Skip("symlinks unavailable:", err)
+	}
+	if _, err := WriteFile(path, []byte("SECRET=2\n")); err != nil {
+		t.Fatal(err)
+	}
+	checkFile(t, target, "untouched", 0o644)
+	info, err := os.Lstat(path + ".bak")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !info.Mode().IsRegular() {
+		t.Errorf(".env.bak is %v, want a regular file", info.Mode().Type())
+	}
+	checkFile(t, path+".bak", "SECRET=1\n", 0o600)
+}
+
+func checkFile(t *testing.T, path, content string, mode os.FileMode) {
+	t.Helper()
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != content {
+		t.Errorf("%s = %q, want %q", filepath.Base(path), data, content)
+	}
+	if runtime.GOOS == "windows" {
+		return
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != mode {
+		t.Errorf("%s mode %v, want %v", filepath.Base(path), info.Mode().Perm(), mode)
+	}
+}
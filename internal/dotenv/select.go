@@ -0,0 +1,55 @@
+package dotenv
+
+import (
+	"fmt"
+	"path"
+	"strings"
+)
+
+// Select keeps the entries whose key matches a pattern in only (every
+// entry when only is empty) and no pattern in exclude. Patterns use
+// path.Match syntax, e.g. STRIPE_* or DB_?. A pattern in only without
+// wildcards that matches nothing is an error, so a typo doesn't silently
+// drop a key.
+func Select(entries []Entry, only, exclude []string) ([]Entry, error) {
+	for _, p := range append(append([]string{}, only...), exclude...) {
+		if _, err := path.Match(p, ""); err != nil {
+			return nil, fmt.Errorf("invalid key pattern %q", p)
+		}
+	}
+	matchAny := func(patterns []string, key string) bool {
+		for _, p := range patterns {
+			if ok, _ := path.Match(p, key); ok {
+				return true
+			}
+		}
+		return false
+	}
+
+	var out []Entry
+	seen := make(map[string]bool)
+	for _, e := range entries {
+		if len(only) > 0 && !matchAny(only, e.Key) {
+			continue
+		}
+		seen[e.Key] = true
+		if !matchAny(exclude, e.Key) {
+			out = append(out, e)
+		}
+	}
+	for _, p := range only {
+		if !strings.ContainsAny(p, `*?[\`) && !seen[p] {
+			return nil, fmt.Errorf("key %s not found", p)
+		}
+	}
+	return out, nil
+}
+
+// Keys returns the distinct keys of entries in order.
+func Keys(entries []Entry) []string {
+	keys := make([]string, 0, len(entries))
+	for _, e := range Unique(entries) {
+		keys = append(keys, e.Key)
+	}
+	return keys
+}
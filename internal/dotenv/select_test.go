@@ -0,0 +1,72 @@
+package dotenv
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestSelect(t *testing.T) {
+	entries := mustParse(t, "DB_HOST=h\nDB_PASS=p\nDB_PORT=5432\nSTRIPE_KEY=sk\nSTRIPE_WEBHOOK=wh\nDEBUG=1\nDB_HOST=h2\n")
+	for _, tc := range []struct {
+		name    string
+		only    []string
+		exclude []string
+		want    []string
+	}{
+		{"everything", nil, nil, []string{"DB_HOST", "DB_PASS", "DB_PORT", "STRIPE_KEY", "STRIPE_WEBHOOK", "DEBUG", "DB_HOST"}},
+		{"literal", []string{"DEBUG"}, nil, []string{"DEBUG"}},
+		{"glob", []string{"STRIPE_*"}, nil, []string{"STRIPE_KEY", "STRIPE_WEBHOOK"}},
+		{"duplicates kept", []string{"DB_HOST"}, nil, []string{"DB_HOST", "DB_HOST"}},
+		{"several patterns in file order", []string{"DEBUG", "STRIPE_KEY"}, nil, []string{"STRIPE_KEY", "DEBUG"}},
+		{"question mark", []string{"DB_PAS?"}, nil, []string{"DB_PASS"}},
+		{"class", []string{"DB_P[AO]*"}, nil, []string{"DB_PASS", "DB_PORT"}},
+		{"exclude only", nil, []string{"DB_*"}, []string{"STRIPE_KEY", "STRIPE_WEBHOOK", "DEBUG"}},
+		{"exclude wins over only", []string{"DB_*"}, []string{"DB_PASS"}, []string{"DB_HOST", "DB_PORT", "DB_HOST"}},
+		{"exclude everything selected", []string{"STRIPE_*"}, []string{"*"}, nil},
+		{"exclude unknown key", nil, []string{"NOPE"}, []string{"DB_HOST", "DB_PASS", "DB_PORT", "STRIPE_KEY", "STRIPE_WEBHOOK", "DEBUG", "DB_HOST"}},
+		{"glob matching nothing", []string{"AWS_*"}, nil, nil},
+		{"case sensitive glob", []string{"db_*"}, nil, nil},
+		// A literal key that is then excluded was still found
+		{"literal then excluded", []string{"DEBUG"}, []string{"DEBUG"}, nil},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := Select(entries, tc.only, tc.exclude)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var keys []string
+			for _, e := range got {
+				keys = append(keys, e.Key)
+			}
+			if !slices.Equal(keys, tc.want) {
+				t.Errorf("Select(only=%q, exclude=%q) = %q, want %q", tc.only, tc.exclude, keys, tc.want)
+			}
+		})
+	}
+}
+
+func TestSelectErrors(t *testing.T) {
+	entries := mustParse(t, "DB_HOST=h\nDEBUG=1\n")
+	for _, tc := range []struct {
+		only    []string
+		exclude []string
+		errHas  string
+	}{
+		{[]string{"DB_HOTS"}, nil, "key DB_HOTS not found"},
+		{[]string{"DB_*", "DEBGU"}, nil, "key DEBGU not found"},
+		{[]string{"debug"}, nil, "key debug not found"},
+		{[]string{"DB_["}, nil, `invalid key pattern "DB_["`},
+		{nil, []string{"["}, `invalid key pattern "["`},
+	} {
+		if _, err := Select(entries, tc.only, tc.exclude); err == nil || !strings.Contains(err.Error(), tc.errHas) {
+			t.Errorf("Select(only=%q, exclude=%q): got %v, want an error containing %q", tc.only, tc.exclude, err, tc.errHas)
+		}
+	}
+}
+
+func TestKeys(t *testing.T) {
+	if got, want := Keys(mustParse(t, "B=1\nA=2\nB=3\n")), []string{"B", "A"}; !slices.Equal(got, want) {
+		t.Errorf("Keys = %q, want %q", got, want)
+	}
+}
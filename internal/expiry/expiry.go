@@ -0,0 +1,127 @@
+// Package expiry parses the --expiry of burnenv create: a duration from
+// now (90s, 45m, 6h, 1h30m; a bare number means minutes) or an absolute
+// time (2026-10-17T09:00Z, 2026-10-17 09:00, 09:00), and describes it for
+// humans ("in 5h 59m at 09:00 local").
+package expiry
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Spec is a parsed expiry: either relative to when the secret is sent, or
+// a fixed point in time.
+type Spec struct {
+	after time.Duration
+	at    time.Time
+}
+
+// After returns a Spec expiring d after the secret is sent.
+func After(d time.Duration) Spec {
+	return Spec{after: d}
+}
+
+// Absolute layouts, with and without a zone (no zone means local time).
+var (
+	zonedLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00"}
+	localLayouts = []string{"2006-01-02T15:04:05", "2006-01-02T15:04", "2006-01-02 15:04:05", "2006-01-02 15:04"}
+	clockLayouts = []string{"15:04", "15:04:05"}
+)
+
+// Parse reads an expiry. A time of day alone (09:00) means its next
+// occurrence. now is only used for that case.
+func Parse(s string, now time.Time) (Spec, error) {
+	s = strings.TrimSpace(s)
+	d, err := time.ParseDuration(s)
+	if n, nerr := strconv.ParseInt(s, 10, 64); nerr == nil {
+		if n > math.MaxInt64/int64(time.Minute) {
+			return Spec{}, fmt.Errorf("expiry %q is too long", s)
+		}
+		d, err = time.Duration(n)*time.Minute, nil
+	}
+	if err == nil {
+		if d <= 0 {
+			return Spec{}, fmt.Errorf("expiry %q must be positive", s)
+		}
+		return After(d), nil
+	}
+	for _, layout := range zonedLayouts {
+		if t, err := time.Parse(layout, s); err == nil {
+			return absolute(s, t, now)
+		}
+	}
+	for _, layout := range localLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return absolute(s, t, now)
+		}
+	}
+	for _, layout := range clockLayouts {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			local := now.In(time.Local)
+			at := time.Date(local.Year(), local.Month(), local.Day(), t.Hour(), t.Minute(), t.Second(), 0, time.Local)
+			if !at.After(local) {
+				at = at.AddDate(0, 0, 1)
+			}
+			return Spec{at: at}, nil
+		}
+	}
+	return Spec{}, fmt.Errorf("invalid expiry %q (use a duration like 90s, 45m or 6h, or a time like 2026-10-17T09:00Z or 09:00)", s)
+}
+
+// absolute is a fixed expiry, which must still be ahead.
+func absolute(s string, t, now time.Time) (Spec, error) {
+	if !t.After(now) {
+		return Spec{}, fmt.Errorf("expiry %q is in the past", s)
+	}
+	return Spec{at: t}, nil
+}
+
+// At returns when a secret sent at now expires.
+func (s Spec) At(now time.Time) time.Time {
+	if !s.at.IsZero() {
+		return s.at
+	}
+	return now.Add(s.after)
+}
+
+// Duration returns how long a secret sent at now lives.
+func (s Spec) Duration(now time.Time) time.Duration {
+	return s.At(now).Sub(now)
+}
+
+// Describe renders an expiry for humans, e.g. "in 5h 59m at 09:00 local".
+// The date is included when it isn't today.
+func Describe(at, now time.Time) string {
+	local := at.In(time.Local)
+	clock := local.Format("15:04")
+	if y, m, d := now.In(time.Local).Date(); local.Year() != y || local.Month() != m || local.Day() != d {
+		clock = local.Format("Jan 2 15:04")
+	}
+	return fmt.Sprintf("in %s at %s local", FormatDuration(at.Sub(now)), clock)
+}
+
+// FormatDuration renders d compactly: "90s" as "1m 30s", "6h" as "6h".
+// Seconds are dropped from durations of an hour or more.
+func FormatDuration(d time.Duration) string {
+	d = d.Round(time.Second)
+	if d >= time.Hour {
+		d = d.Round(time.Minute)
+	}
+	if d <= 0 {
+		return "0s"
+	}
+	var parts []string
+	for _, u := range []struct {
+		unit time.Duration
+		name string
+	}{{time.Hour, "h"}, {time.Minute, "m"}, {time.Second, "s"}} {
+		if n := d / u.unit; n > 0 {
+			parts = append(parts, fmt.Sprintf("%d%s", n, u.name))
+			d -= n * u.unit
+		}
+	}
+	return strings.Join(parts, " ")
+}
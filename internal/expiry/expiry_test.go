@@ -0,0 +1,110 @@
+package expiry
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		in   string
+		want time.Time // Expiry of a secret sent at now
+	}{
+		{"90s", now.Add(90 * time.Second)},
+		{"45m", now.Add(45 * time.Minute)},
+		{"6h", now.Add(6 * time.Hour)},
+		{"1h30m", now.Add(90 * time.Minute)},
+		{"30", now.Add(30 * time.Minute)},
+		{" 15m\n", now.Add(15 * time.Minute)},
+		{"2026-10-18T09:00Z", time.Date(2026, 10, 18, 9, 0, 0, 0, time.UTC)},
+		{"2026-10-18T09:00:30+02:00", time.Date(2026, 10, 18, 7, 0, 30, 0, time.UTC)},
+		{"2026-10-17 09:00", time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)},
+		{"2026-10-17T09:00:05", time.Date(2026, 10, 17, 9, 0, 5, 0, time.Local)},
+		{"09:00", time.Date(2026, 10, 17, 9, 0, 0, 0, time.Local)},
+		{"07:30", time.Date(2026, 10, 18, 7, 30, 0, 0, time.Local)}, // Already passed today
+		{"08:00", time.Date(2026, 10, 18, 8, 0, 0, 0, time.Local)},  // Now is not ahead
+	} {
+		spec, err := Parse(tc.in, now)
+		if err != nil {
+			t.Errorf("Parse(%q): %v", tc.in, err)
+			continue
+		}
+		if got := spec.At(now); !got.Equal(tc.want) {
+			t.Errorf("Parse(%q).At = %v, want %v", tc.in, got, tc.want)
+		}
+		if got := spec.Duration(now); got != tc.want.Sub(now) {
+			t.Errorf("Parse(%q).Duration = %v, want %v", tc.in, got, tc.want.Sub(now))
+		}
+	}
+
+	for _, tc := range []struct {
+		in     string
+		errHas string
+	}{
+		{"0", "must be positive"},
+		{"-5", "must be positive"},
+		{"-1h", "must be positive"},
+		{"0s", "must be positive"},
+		{"2026-10-16T07:00Z", "in the past"},
+		{"2026-10-17 08:00", "in the past"},
+		{"2000-01-01T00:00:00Z", "in the past"},
+		{"153722868", "too long"}, // Minutes past the int64 nanosecond range
+		{"9223372036854775807", "too long"},
+		{"99999999999999999999", "invalid expiry"},
+		{"99999999h", "invalid expiry"},
+		{"", "invalid expiry"},
+		{"tomorrow", "invalid expiry"},
+		{"25:00", "invalid expiry"},
+	} {
+		if _, err := Parse(tc.in, now); err == nil || !strings.Contains(err.Error(), tc.errHas) {
+			t.Errorf("Parse(%q): got %v, want an error containing %q", tc.in, err, tc.errHas)
+		}
+	}
+
+	// The largest whole number of minutes still fits
+	if spec, err := Parse("153722867", now); err != nil || spec.after <= 0 {
+		t.Errorf("Parse(max minutes) = %v, %v", spec.after, err)
+	}
+}
+
+func TestDescribe(t *testing.T) {
+	now := time.Date(2026, 10, 17, 8, 0, 0, 0, time.Local)
+	for _, tc := range []struct {
+		at   time.Time
+		want string
+	}{
+		{now.Add(6 * time.Hour), "in 6h at 14:00 local"},
+		{now.Add(5*time.Hour + 59*time.Minute), "in 5h 59m at 13:59 local"},
+		{now.Add(90 * time.Second), "in 1m 30s at 08:01 local"},
+		{now.Add(20 * time.Hour), "in 20h at Oct 18 04:00 local"},
+		{now.Add(-time.Minute), "in 0s at 07:59 local"},
+	} {
+		if got := Describe(tc.at, now); got != tc.want {
+			t.Errorf("Describe(%v) = %q, want %q", tc.at, got, tc.want)
+		}
+	}
+}
+
+func TestFormatDuration(t *testing.T) {
+	for _, tc := range []struct {
+		d    time.Duration
+		want string
+	}{
+		{0, "0s"},
+		{-time.Hour, "0s"},
+		{400 * time.Millisecond, "0s"},
+		{time.Second, "1s"},
+		{90 * time.Second, "1m 30s"},
+		{45 * time.Minute, "45m"},
+		{59*time.Minute + 59*time.Second + 600*time.Millisecond, "1h"},
+		{time.Hour + 30*time.Second, "1h 1m"},
+		{6 * time.Hour, "6h"},
+		{49*time.Hour + 5*time.Minute + 10*time.Second, "49h 5m"},
+	} {
+		if got := FormatDuration(tc.d); got != tc.want {
+			t.Errorf("FormatDuration(%v) = %q, want %q", tc.d, got, tc.want)
+		}
+	}
+}
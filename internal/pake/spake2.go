@@ -0,0 +1,146 @@
+// Package pake implements SPAKE2 (RFC 9382) over edwards25519.
+// It lets two parties who share a short, low-entropy code agree on a strong
+// key through an untrusted relay. A passive relay learns nothing, and an
+// active attacker gets exactly one password guess per exchange.
+package pake
+
+import (
+	"crypto/hkdf"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+
+	"filippo.io/edwards25519"
+	"golang.org/x/crypto/argon2"
+)
+
+// Role identifies which side of the exchange a State belongs to.
+type Role int
+
+const (
+	RoleA Role = iota // Initiator (burnenv send)
+	RoleB             // Responder (burnenv receive)
+)
+
+// KeySize is the length of the derived session key (AES-256).
+const KeySize = 32
+
+// Password stretching: cheap enough for an interactive CLI, but makes
+// per-exchange guessing by a malicious relay a little more expensive.
+const (
+	pwTime    = 1
+	pwMemory  = 64 * 1024 // 64 MiB
+	pwThreads = 4
+	pwSalt    = "burnenv-spake2-edwards25519"
+)
+
+// M and N are the fixed edwards25519 points from RFC 9382, section 4.
+var (
+	pointM = mustPoint("d048032c6ea0b6d697ddc2e86bda85a33adac920f1bf18e1b0c6d166a5cecdaf")
+	pointN = mustPoint("d3bfb518f44f3430f29d0c92af503865a1ed3281dc69b35dd868ba85f886c4ab")
+)
+
+// ErrInvalidMessage is returned when the peer's message is not a valid point.
+var ErrInvalidMessage = errors.New("pake: invalid peer message")
+
+// State holds one side's ephemeral secret until the peer message arrives.
+type State struct {
+	role     Role
+	idA, idB []byte
+	w        *edwards25519.Scalar
+	x        *edwards25519.Scalar
+	msg      []byte
+}
+
+// Start begins an exchange and returns the message to send to the peer.
+// idA and idB name the two sides and must match on both ends.
+func Start(role Role, password, idA, idB []byte) (*State, []byte, error) {
+	if len(password) == 0 {
+		return nil, nil, errors.New("pake: empty password")
+	}
+	w, err := new(edwards25519.Scalar).SetUniformBytes(
+		argon2.IDKey(password, []byte(pwSalt), pwTime, pwMemory, pwThreads, 64))
+	if err != nil {
+		return nil, nil, err
+	}
+	seed := make([]byte, 64)
+	if _, err := rand.Read(seed); err != nil {
+		return nil, nil, fmt.Errorf("pake: random scalar: %w", err)
+	}
+	x, err := new(edwards25519.Scalar).SetUniformBytes(seed)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	blind := pointM
+	if role == RoleB {
+		blind = pointN
+	}
+	// pA = x*G + w*M  (or pB = y*G + w*N)
+	p := new(edwards25519.Point).ScalarBaseMult(x)
+	p.Add(p, new(edwards25519.Point).ScalarMult(w, blind))
+
+	s := &State{role: role, idA: idA, idB: idB, w: w, x: x, msg: p.Bytes()}
+	return s, s.msg, nil
+}
+
+// SetIdentities sets the party identities bound into the transcript.
+// The message returned by Start does not depend on them, so they may be
+// set after it has been sent (e.g. once the server assigns a channel).
+func (s *State) SetIdentities(idA, idB []byte) {
+	s.idA, s.idB = idA, idB
+}
+
+// Finish consumes the peer's message and returns the shared session key.
+// Both sides get the same key only if they used the same password.
+func (s *State) Finish(peer []byte) ([]byte, error) {
+	q, err := new(edwards25519.Point).SetBytes(peer)
+	if err != nil {
+		return nil, ErrInvalidMessage
+	}
+	// The identity and other small-order points carry no contribution
+	if new(edwards25519.Point).MultByCofactor(q).Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, ErrInvalidMessage
+	}
+	unblind := pointN
+	if s.role == RoleB {
+		unblind = pointM
+	}
+	// K = h * x * (peer - w*N)   (cofactor h = 8 clears small-order components)
+	q.Subtract(q, new(edwards25519.Point).ScalarMult(s.w, unblind))
+	k := new(edwards25519.Point).ScalarMult(s.x, q)
+	k.MultByCofactor(k)
+	if k.Equal(edwards25519.NewIdentityPoint()) == 1 {
+		return nil, ErrInvalidMessage
+	}
+
+	pA, pB := s.msg, peer
+	if s.role == RoleB {
+		pA, pB = peer, s.msg
+	}
+	h := sha256.New()
+	for _, part := range [][]byte{s.idA, s.idB, pA, pB, k.Bytes(), s.w.Bytes()} {
+		var n [8]byte
+		binary.LittleEndian.PutUint64(n[:], uint64(len(part)))
+		h.Write(n[:])
+		h.Write(part)
+	}
+	tt := h.Sum(nil)
+	ke := tt[:len(tt)/2]
+	return hkdf.Key(sha256.New, ke, nil, "burnenv session key", KeySize)
+}
+
+func mustPoint(s string) *edwards25519.Point {
+	b, err := hex.DecodeString(s)
+	if err != nil {
+		panic(err)
+	}
+	p, err := new(edwards25519.Point).SetBytes(b)
+	if err != nil {
+		panic(err)
+	}
+	return p
+}
@@ -0,0 +1,136 @@
+package pake
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"filippo.io/edwards25519"
+)
+
+// exchange runs both sides and returns their keys.
+func exchange(t *testing.T, pwA, pwB string, idA, idB []byte) (keyA, keyB []byte) {
+	t.Helper()
+	a, msgA, err := Start(RoleA, []byte(pwA), idA, idB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, msgB, err := Start(RoleB, []byte(pwB), idA, idB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if keyA, err = a.Finish(msgB); err != nil {
+		t.Fatal(err)
+	}
+	if keyB, err = b.Finish(msgA); err != nil {
+		t.Fatal(err)
+	}
+	return keyA, keyB
+}
+
+func TestSameKey(t *testing.T) {
+	keyA, keyB := exchange(t, "crossover-clockwork", "crossover-clockwork", []byte("7"), []byte("7/0"))
+	if len(keyA) != KeySize {
+		t.Fatalf("key is %d bytes, want %d", len(keyA), KeySize)
+	}
+	if !bytes.Equal(keyA, keyB) {
+		t.Error("same password gave different keys")
+	}
+
+	// A fresh exchange uses fresh scalars
+	again, _ := exchange(t, "crossover-clockwork", "crossover-clockwork", []byte("7"), []byte("7/0"))
+	if bytes.Equal(keyA, again) {
+		t.Error("two exchanges gave the same key")
+	}
+}
+
+func TestWrongPassword(t *testing.T) {
+	keyA, keyB := exchange(t, "crossover-clockwork", "crossover-clockworm", []byte("7"), []byte("7/0"))
+	if bytes.Equal(keyA, keyB) {
+		t.Error("different passwords gave the same key")
+	}
+}
+
+func TestIdentityBinding(t *testing.T) {
+	a, msgA, err := Start(RoleA, []byte("pw"), nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, msgB, err := Start(RoleB, []byte("pw"), []byte("7"), []byte("7/0"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	keyB, err := b.Finish(msgA)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Identities set after Start, as burnenv send does once the channel is known
+	a.SetIdentities([]byte("7"), []byte("7/0"))
+	keyA, err := a.Finish(msgB)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(keyA, keyB) {
+		t.Error("identities set after Start gave a different key")
+	}
+
+	for _, ids := range [][2]string{{"8", "7/0"}, {"7", "7/1"}, {"7/0", "7"}, {"", ""}} {
+		a.SetIdentities([]byte(ids[0]), []byte(ids[1]))
+		key, err := a.Finish(msgB)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if bytes.Equal(key, keyB) {
+			t.Errorf("identities %q agreed with 7, 7/0", ids)
+		}
+	}
+}
+
+func TestInvalidPeerMessage(t *testing.T) {
+	identity := edwards25519.NewIdentityPoint().Bytes()
+	// A point of order 8 on edwards25519
+	order8 := []byte{
+		0x26, 0xe8, 0x95, 0x8f, 0xc2, 0xb2, 0x27, 0xb0, 0x45, 0xc3, 0xf4, 0x89, 0xf2, 0xef, 0x98, 0xf0,
+		0xd5, 0xdf, 0xac, 0x05, 0xd3, 0xc6, 0x33, 0x39, 0xb1, 0x38, 0x02, 0x88, 0x6d, 0x53, 0xfc, 0x05,
+	}
+	if p, err := new(edwards25519.Point).SetBytes(order8); err != nil || new(edwards25519.Point).MultByCofactor(p).Equal(edwards25519.NewIdentityPoint()) != 1 {
+		t.Fatal("test vector is not a small-order point")
+	}
+	offCurve := make([]byte, 32)
+	for offCurve[0] = 2; ; offCurve[0]++ {
+		if _, err := new(edwards25519.Point).SetBytes(offCurve); err != nil {
+			break
+		}
+	}
+
+	for _, tc := range []struct {
+		name string
+		msg  []byte
+	}{
+		{"empty", nil},
+		{"short", identity[:31]},
+		{"long", append(bytes.Clone(identity), 0)},
+		{"not a point", offCurve},
+		{"identity", identity},
+		{"small order", order8},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			for _, role := range []Role{RoleA, RoleB} {
+				s, _, err := Start(role, []byte("pw"), []byte("7"), []byte("7/0"))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if _, err := s.Finish(tc.msg); !errors.Is(err, ErrInvalidMessage) {
+					t.Errorf("role %d: got %v, want ErrInvalidMessage", role, err)
+				}
+			}
+		})
+	}
+}
+
+func TestEmptyPassword(t *testing.T) {
+	if _, _, err := Start(RoleA, nil, nil, nil); err == nil {
+		t.Error("empty password accepted")
+	}
+}
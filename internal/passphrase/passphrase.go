@@ -0,0 +1,80 @@
+// Package passphrase generates diceware passphrases for burnenv create
+// --generate-password and estimates how hard a password is to guess.
+//
+// The word list is the EFF large wordlist (7776 words, 12.9 bits each),
+// https://www.eff.org/deeplinks/2016/07/new-wordlists-random-passphrases,
+// by the Electronic Frontier Foundation, licensed CC BY 3.0 US.
+package passphrase
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	_ "embed"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+)
+
+// DefaultWords is the passphrase length of --generate-password; six words
+// give 77 bits, out of reach of an offline attack even against a fast KDF.
+const DefaultWords = 6
+
+// MinWords is the shortest passphrase Generate produces.
+const MinWords = 4
+
+// MaxWords is the longest: 32 words (413 bits) is far past any attack,
+// and the limit keeps --words from allocating without bound.
+const MaxWords = 32
+
+// Separator joins the words of a generated passphrase.
+const Separator = "-"
+
+//go:embed eff_large_wordlist.txt
+var effLarge []byte
+
+// words is the EFF list in dice-roll order; the rolls are not needed.
+var words = func() []string {
+	var out []string
+	sc := bufio.NewScanner(bytes.NewReader(effLarge))
+	for sc.Scan() {
+		if _, w, ok := strings.Cut(sc.Text(), "\t"); ok {
+			out = append(out, w)
+		}
+	}
+	return out
+}()
+
+// wordSet indexes words for the strength estimate.
+var wordSet = func() map[string]struct{} {
+	m := make(map[string]struct{}, len(words))
+	for _, w := range words {
+		m[w] = struct{}{}
+	}
+	return m
+}()
+
+// Generate returns n words chosen uniformly at random from the EFF list.
+func Generate(n int) (string, error) {
+	if n < MinWords {
+		return "", fmt.Errorf("a passphrase needs at least %d words", MinWords)
+	}
+	if n > MaxWords {
+		return "", fmt.Errorf("a passphrase has at most %d words", MaxWords)
+	}
+	picked := make([]string, n)
+	for i := range picked {
+		j, err := rand.Int(rand.Reader, big.NewInt(int64(len(words))))
+		if err != nil {
+			return "", err
+		}
+		picked[i] = words[j.Int64()]
+	}
+	return strings.Join(picked, Separator), nil
+}
+
+// Bits returns the entropy of an n-word generated passphrase.
+func Bits(n int) float64 {
+	return float64(n) * math.Log2(float64(len(words)))
+}
@@ -0,0 +1,244 @@
+package passphrase
+
+import (
+	"math"
+	"strings"
+	"unicode"
+)
+
+// Strength is a zxcvbn-style estimate: the password is split into the
+// cheapest sequence of patterns (common passwords, dictionary words,
+// repeats, sequences, keyboard runs, years, single characters) and the
+// guesses for each are multiplied.
+type Strength struct {
+	Score   int     // 0 (guessable in seconds) to 4 (out of reach)
+	Bits    float64 // log2 of the estimated guesses
+	Warning string  // The pattern that weakens it most; empty if strong
+}
+
+// Labels names each score.
+var Labels = []string{"very weak", "weak", "fair", "strong", "very strong"}
+
+// Label returns the name of s.Score.
+func (s Strength) Label() string {
+	return Labels[s.Score]
+}
+
+const (
+	maxEstimateRunes = 256 // Longer passwords are judged on their start
+	maxMatchRunes    = 32  // Longest single pattern considered
+)
+
+// Score thresholds in log10 guesses, as in zxcvbn.
+var scoreThresholds = []float64{3, 6, 8, 10}
+
+// commonPasswords are among the most used passwords, most common first.
+var commonPasswords = rankMap([]string{
+	"123456", "password", "123456789", "12345678", "12345", "qwerty", "1234567", "111111",
+	"1234567890", "123123", "abc123", "1234", "password1", "iloveyou", "000000", "qwerty123",
+	"dragon", "monkey", "654321", "666666", "123321", "letmein", "football", "baseball",
+	"welcome", "admin", "sunshine", "master", "shadow", "princess", "trustno1", "superman",
+	"batman", "hello", "freedom", "whatever", "qazwsx", "michael", "starwars", "ninja",
+	"mustang", "passw0rd", "p@ssw0rd", "secret", "changeme", "root", "toor", "test",
+	"guest", "login", "access", "flower", "loveme", "charlie", "jordan", "harley",
+	"ranger", "hunter", "buster", "soccer", "hockey", "killer", "george", "andrew",
+	"thomas", "jessica", "pepper", "daniel", "summer", "ashley", "computer", "internet",
+	"cheese", "matrix", "maggie", "ginger", "cookie", "chocolate", "banana", "orange",
+	"purple", "silver", "tigger", "robert", "pass", "default", "qwertyuiop", "asdfgh",
+	"zxcvbnm", "1q2w3e4r", "1qaz2wsx", "aa123456", "abcd1234", "lovely", "7777777", "121212",
+	"987654321", "zaq12wsx", "qwe123", "asdf", "asdfghjkl", "burnenv", "secure", "mypassword",
+})
+
+func rankMap(words []string) map[string]int {
+	m := make(map[string]int, len(words))
+	for i, w := range words {
+		m[w] = i + 1
+	}
+	return m
+}
+
+// keyboardRows are scanned forwards and backwards for runs like "qwer".
+var keyboardRows = []string{"`1234567890-=", "qwertyuiop[]\\", "asdfghjkl;'", "zxcvbnm,./"}
+
+// leet undoes common character substitutions (p4ssw0rd).
+var leet = strings.NewReplacer("4", "a", "@", "a", "3", "e", "1", "i", "!", "i", "0", "o", "5", "s", "$", "s", "7", "t")
+
+type match struct {
+	start int
+	bits  float64
+	warn  string
+}
+
+// Estimate rates how hard password is to guess. Its working copies of
+// the password are wiped before returning.
+func Estimate(password []byte) Strength {
+	orig := []rune(string(password))
+	defer clear(orig)
+	if len(orig) > maxEstimateRunes {
+		orig = orig[:maxEstimateRunes]
+	}
+	n := len(orig)
+	if n == 0 {
+		return Strength{Warning: "The password is empty"}
+	}
+	lower := make([]rune, n)
+	defer clear(lower)
+	for i, r := range orig {
+		lower[i] = unicode.ToLower(r)
+	}
+
+	// best[j] is the cheapest split of the first j runes
+	best := make([]float64, n+1)
+	from := make([]match, n+1)
+	for j := 1; j <= n; j++ {
+		best[j] = math.Inf(1)
+		for i := max(0, j-maxMatchRunes); i < j; i++ {
+			for _, m := range matchesAt(orig[i:j], lower[i:j]) {
+				if b := best[i] + m.bits; b < best[j] {
+					best[j] = b
+					from[j] = match{start: i, bits: m.bits, warn: m.warn}
+				}
+			}
+		}
+	}
+
+	s := Strength{Bits: best[n]}
+	log10 := best[n] * math.Log10(2)
+	for s.Score < len(scoreThresholds) && log10 >= scoreThresholds[s.Score] {
+		s.Score++
+	}
+	if s.Score >= 3 {
+		return s
+	}
+	// Report the pattern covering the most of the password
+	longest := 0
+	for j := n; j > 0; j = from[j].start {
+		if l := j - from[j].start; from[j].warn != "" && l > longest {
+			longest, s.Warning = l, from[j].warn
+		}
+	}
+	if s.Warning == "" {
+		s.Warning = "Short passwords are easy to guess; use a few random words"
+	}
+	return s
+}
+
+// matchesAt returns the patterns that cover the whole of a substring.
+func matchesAt(orig, lower []rune) []match {
+	n := len(lower)
+	if n == 1 {
+		return []match{{bits: math.Log2(cardinality(orig[0]))}}
+	}
+	var out []match
+	s := string(lower)
+	if n >= 3 {
+		if rank, ok := commonPasswords[s]; ok {
+			out = append(out, match{bits: math.Log2(float64(rank)) + caseBits(orig), warn: "This is a commonly used password"})
+		}
+		if _, ok := wordSet[s]; ok {
+			out = append(out, match{bits: math.Log2(float64(len(words))) + caseBits(orig), warn: "Single words are easy to guess; use several"})
+		}
+		if u := leet.Replace(s); u != s {
+			if rank, ok := commonPasswords[u]; ok {
+				out = append(out, match{bits: math.Log2(float64(rank)) + caseBits(orig) + 2, warn: "Substitutions like @ for a don't help much"})
+			}
+			if _, ok := wordSet[u]; ok {
+				out = append(out, match{bits: math.Log2(float64(len(words))) + caseBits(orig) + 2, warn: "Substitutions like @ for a don't help much"})
+			}
+		}
+		if repeated(lower) {
+			out = append(out, match{bits: math.Log2(cardinality(orig[0]) * float64(n)), warn: "Repeats like aaa are easy to guess"})
+		}
+		if step := sequenceStep(lower); step != 0 {
+			base := 26.0
+			switch {
+			case strings.ContainsRune("a1z90", lower[0]):
+				base = 4
+			case unicode.IsDigit(lower[0]):
+				base = 10
+			}
+			if step < 0 {
+				base *= 2
+			}
+			out = append(out, match{bits: math.Log2(base * float64(n)), warn: "Sequences like abc or 6543 are easy to guess"})
+		}
+	}
+	if n >= 4 && onKeyboard(s) {
+		out = append(out, match{bits: math.Log2(10 * float64(n)), warn: "Keyboard patterns like qwerty are easy to guess"})
+	}
+	if n == 4 && s >= "1900" && s <= "2039" && strings.Trim(s, "0123456789") == "" {
+		out = append(out, match{bits: math.Log2(140), warn: "Recent years are easy to guess"})
+	}
+	return out
+}
+
+// cardinality is the number of characters an attacker tries for r.
+func cardinality(r rune) float64 {
+	switch {
+	case r >= '0' && r <= '9':
+		return 10
+	case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		return 26
+	case r < 128:
+		return 33
+	}
+	return 100
+}
+
+// caseBits is the extra guessing for capitals: one bit for a capitalised
+// or all-caps word, one per capital otherwise.
+func caseBits(orig []rune) float64 {
+	upper := 0
+	for _, r := range orig {
+		if unicode.IsUpper(r) {
+			upper++
+		}
+	}
+	switch {
+	case upper == 0:
+		return 0
+	case upper == len(orig), upper == 1 && unicode.IsUpper(orig[0]):
+		return 1
+	}
+	return float64(upper)
+}
+
+func repeated(rs []rune) bool {
+	for _, r := range rs[1:] {
+		if r != rs[0] {
+			return false
+		}
+	}
+	return true
+}
+
+// sequenceStep returns 1 or -1 for runs like "abc" or "321", else 0.
+func sequenceStep(rs []rune) int {
+	step := int(rs[1] - rs[0])
+	if step != 1 && step != -1 {
+		return 0
+	}
+	for i := 2; i < len(rs); i++ {
+		if int(rs[i]-rs[i-1]) != step {
+			return 0
+		}
+	}
+	return step
+}
+
+func onKeyboard(s string) bool {
+	for _, row := range keyboardRows {
+		if strings.Contains(row, s) || strings.Contains(reverse(row), s) {
+			return true
+		}
+	}
+	return false
+}
+
+func reverse(s string) string {
+	rs := []rune(s)
+	for i, j := 0, len(rs)-1; i < j; i, j = i+1, j-1 {
+		rs[i], rs[j] = rs[j], rs[i]
+	}
+	return string(rs)
+}
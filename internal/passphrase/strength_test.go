@@ -0,0 +1,122 @@
+package passphrase
+
+import (
+	"math"
+	"strings"
+	"testing"
+)
+
+func TestEstimate(t *testing.T) {
+	diceware := func(idx ...int) string {
+		var picked []string
+		for _, i := range idx {
+			picked = append(picked, words[i])
+		}
+		return strings.Join(picked, Separator)
+	}
+	for _, tc := range []struct {
+		name     string
+		password string
+		score    int
+		warnHas  string // "" when the password is strong enough to need none
+	}{
+		// Diceware-shaped
+		{"one word", "zebra", 1, "Single words"},
+		{"one capitalised word", "Zebra", 1, "Single words"},
+		{"two words", "abacus-zebra", 3, ""},
+		{"four words", diceware(100, 2000, 4000, 7000), 4, ""},
+		{"six words", diceware(1, 1111, 2222, 3333, 4444, 5555), 4, ""},
+		{"words with spaces", "correct horse battery staple", 4, ""},
+		{"words run together", "correcthorsebatterystaple", 4, ""},
+
+		// Common and substituted
+		{"common", "password", 0, "commonly used"},
+		{"common with digit", "Password1", 0, "commonly used"},
+		{"leet", "P@ssw0rd", 0, "Substitutions"},
+		{"common plus year", "dragon1998", 1, "commonly used"},
+
+		// Repeated
+		{"repeat", "aaaaaaaaaaaa", 0, "Repeats"},
+		{"long repeat", strings.Repeat("1", 38), 1, "Repeats"},
+
+		// Sequential
+		{"alphabet", "abcdefghijklmnop", 0, "Sequences"},
+		{"descending digits", "9876543210", 0, "Sequences"},
+		{"descending letters", "zyxwvuts", 0, "Sequences"},
+		{"keyboard", "poiuytrewq", 0, "Keyboard"},
+		{"year", "1987", 0, "Recent years"},
+
+		// Short
+		{"empty", "", 0, "empty"},
+		{"one char", "a", 0, "Short passwords"},
+		{"two chars", "ab", 0, "Short passwords"},
+		{"five random", "x7#kQ", 2, "Short passwords"},
+		{"eight random", "x7#kQ2!m", 4, ""},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			s := Estimate([]byte(tc.password))
+			if s.Score != tc.score {
+				t.Errorf("Estimate(%q).Score = %d (%.1f bits), want %d", tc.password, s.Score, s.Bits, tc.score)
+			}
+			if (tc.warnHas == "" && s.Warning != "") || !strings.Contains(s.Warning, tc.warnHas) {
+				t.Errorf("Estimate(%q).Warning = %q, want one containing %q", tc.password, s.Warning, tc.warnHas)
+			}
+		})
+	}
+}
+
+// TestScoreThresholds checks the score counts the zxcvbn thresholds met
+// by the guesses, and that only scores below 3 carry a warning.
+func TestScoreThresholds(t *testing.T) {
+	for _, p := range []string{"", "a", "7", "73", "739", "7391", "73915", "739152", "7391528", "73915284", "739152846", "7391528460", "73915284601", "x7#kQ", "abacus-zebra", "zebra"} {
+		s := Estimate([]byte(p))
+		want := 0
+		for _, th := range scoreThresholds {
+			if s.Bits*math.Log10(2) >= th {
+				want++
+			}
+		}
+		if s.Score != want {
+			t.Errorf("Estimate(%q): score %d for %.2f log10 guesses, want %d", p, s.Score, s.Bits*math.Log10(2), want)
+		}
+		if (s.Warning == "") != (s.Score >= 3) {
+			t.Errorf("Estimate(%q): score %d with warning %q", p, s.Score, s.Warning)
+		}
+	}
+
+	// Random digits cost about one log10 guess each, so scores step up at
+	// 3, 6, 8 and 10 digits
+	for digits, want := range map[string]int{"73": 0, "7391": 1, "7391528": 2, "739152846": 3, "73915284601": 4} {
+		if s := Estimate([]byte(digits)); s.Score != want {
+			t.Errorf("Estimate(%q).Score = %d, want %d", digits, s.Score, want)
+		}
+	}
+}
+
+// TestEstimateMonotonic checks appending random characters never lowers
+// the score, and that inputs past maxEstimateRunes are judged on their
+// start.
+func TestEstimateMonotonic(t *testing.T) {
+	const chars = "x7#kQ2!mZp4&Rv9_tW"
+	prev := 0
+	for i := 1; i <= len(chars); i++ {
+		s := Estimate([]byte(chars[:i]))
+		if s.Score < prev {
+			t.Errorf("Estimate(%q).Score = %d, below %d for a prefix", chars[:i], s.Score, prev)
+		}
+		prev = s.Score
+	}
+
+	long := strings.Repeat("a", maxEstimateRunes)
+	if a, b := Estimate([]byte(long)), Estimate([]byte(long+chars)); a != b {
+		t.Errorf("characters past %d runes changed the estimate: %+v, %+v", maxEstimateRunes, a, b)
+	}
+}
+
+func TestLabel(t *testing.T) {
+	for score, want := range Labels {
+		if got := (Strength{Score: score}).Label(); got != want {
+			t.Errorf("Label(%d) = %q, want %q", score, got, want)
+		}
+	}
+}
@@ -0,0 +1,50 @@
+// Package pow implements a hashcash-style proof of work used to make bulk
+// drop creation on public servers expensive. The server issues a challenge
+// string and a difficulty; the client finds a nonce such that
+// SHA-256(challenge ":" nonce) starts with that many zero bits.
+package pow
+
+import (
+	"crypto/sha256"
+	"math/bits"
+	"strconv"
+)
+
+// Request headers carrying a solved challenge on POST /v1/drop.
+const (
+	HeaderChallenge = "X-BurnEnv-Challenge"
+	HeaderNonce     = "X-BurnEnv-Nonce"
+)
+
+// MaxDifficulty bounds the work a server can demand from a client.
+const MaxDifficulty = 32
+
+// Check reports whether nonce solves challenge at the given difficulty.
+func Check(challenge, nonce string, difficulty int) bool {
+	sum := sha256.Sum256([]byte(challenge + ":" + nonce))
+	return leadingZeroBits(sum[:]) >= difficulty
+}
+
+// Solve finds a nonce for challenge. Expected cost is 2^difficulty hashes.
+func Solve(challenge string, difficulty int) string {
+	prefix := []byte(challenge + ":")
+	buf := make([]byte, 0, len(prefix)+20)
+	for n := uint64(0); ; n++ {
+		buf = strconv.AppendUint(append(buf[:0], prefix...), n, 10)
+		sum := sha256.Sum256(buf)
+		if leadingZeroBits(sum[:]) >= difficulty {
+			return strconv.FormatUint(n, 10)
+		}
+	}
+}
+
+func leadingZeroBits(b []byte) int {
+	n := 0
+	for _, c := range b {
+		if c != 0 {
+			return n + bits.LeadingZeros8(c)
+		}
+		n += 8
+	}
+	return n
+}
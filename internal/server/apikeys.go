@@ -0,0 +1,171 @@
+package server
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// KeyPolicy limits what a single API key may create.
+// Zero values mean "server default".
+type KeyPolicy struct {
+	MaxExpirySeconds int64 `json:"max_expiry_seconds,omitempty"`
+	MaxViews         int   `json:"max_views,omitempty"`
+	MaxSize          int   `json:"max_size,omitempty"`        // Max ciphertext length (base64 bytes)
+	RatePerMinute    int   `json:"rate_per_minute,omitempty"` // Creates per minute (burst = same)
+}
+
+// KeyEntry is one API key in the keys file. Only the SHA-256 of the key
+// is stored, so the file is not a credential by itself.
+type KeyEntry struct {
+	Name   string    `json:"name"`
+	SHA256 string    `json:"sha256"`
+	Policy KeyPolicy `json:"policy"`
+}
+
+// APIKey is a loaded key with its policy and rate-limiter state.
+type APIKey struct {
+	Name   string
+	Policy KeyPolicy
+
+	hash   [sha256.Size]byte
+	mu     sync.Mutex
+	tokens float64
+	last   time.Time
+}
+
+// KeyRing holds the API keys allowed to create drops.
+type KeyRing struct {
+	keys []*APIKey
+}
+
+// LoadKeyRing reads a JSON keys file:
+//
+//	{"keys": [{"name": "ci", "sha256": "<hex>", "policy": {"max_views": 5}}]}
+func LoadKeyRing(path string) (*KeyRing, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read keys file: %w", err)
+	}
+	var file struct {
+		Keys []KeyEntry `json:"keys"`
+	}
+	if err := json.Unmarshal(data, &file); err != nil {
+		return nil, fmt.Errorf("parse keys file: %w", err)
+	}
+	if len(file.Keys) == 0 {
+		return nil, fmt.Errorf("keys file %s contains no keys", path)
+	}
+	ring := &KeyRing{}
+	for i, e := range file.Keys {
+		h, err := hex.DecodeString(strings.TrimPrefix(e.SHA256, "sha256:"))
+		if err != nil || len(h) != sha256.Size {
+			return nil, fmt.Errorf("keys file: entry %d (%q): invalid sha256", i, e.Name)
+		}
+		if e.Name == "" {
+			return nil, fmt.Errorf("keys file: entry %d: missing name", i)
+		}
+		k := &APIKey{Name: e.Name, Policy: e.Policy, tokens: float64(e.Policy.RatePerMinute), last: time.Now()}
+		copy(k.hash[:], h)
+		ring.keys = append(ring.keys, k)
+	}
+	return ring, nil
+}
+
+// HashAPIKey returns the hex SHA-256 of a key, as stored in the keys file.
+func HashAPIKey(key string) string {
+	h := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(h[:])
+}
+
+// Authenticate finds the key matching an "Authorization: Bearer" header.
+func (r *KeyRing) Authenticate(header string) (*APIKey, bool) {
+	token, ok := strings.CutPrefix(header, "Bearer ")
+	if !ok || token == "" {
+		return nil, false
+	}
+	h := sha256.Sum256([]byte(strings.TrimSpace(token)))
+	for _, k := range r.keys {
+		if subtle.ConstantTimeCompare(h[:], k.hash[:]) == 1 {
+			return k, true
+		}
+	}
+	return nil, false
+}
+
+// allow takes one token from the key's bucket.
+func (k *APIKey) allow() bool {
+	rate := k.Policy.RatePerMinute
+	if rate <= 0 {
+		return true
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	now := time.Now()
+	k.tokens += now.Sub(k.last).Minutes() * float64(rate)
+	if k.tokens > float64(rate) {
+		k.tokens = float64(rate)
+	}
+	k.last = now
+	if k.tokens < 1 {
+		return false
+	}
+	k.tokens--
+	return true
+}
+
+// checkPolicy enforces a key's policy on an already-validated request.
+func (k *APIKey) checkPolicy(req *dropCreateRequest) (int, string) {
+	p := k.Policy
+	if status, msg := k.checkSize(len(req.Ciphertext)); status != 0 {
+		return status, msg
+	}
+	if status, msg := k.checkExpiry(req.Expiry); status != 0 {
+		return status, msg
+	}
+	if p.MaxViews > 0 && req.MaxViews > p.MaxViews {
+		return http.StatusForbidden,
+			fmt.Sprintf("max_views exceeds this key's limit (%d)", p.MaxViews)
+	}
+	if !k.allow() {
+		return http.StatusTooManyRequests, "rate limit exceeded for this API key"
+	}
+	return 0, ""
+}
+
+// checkCodePolicy enforces a key's policy on opening a short code, which
+// counts against the same rate limit as creating a drop.
+func (k *APIKey) checkCodePolicy(expiry int64) (int, string) {
+	if status, msg := k.checkExpiry(expiry); status != 0 {
+		return status, msg
+	}
+	if !k.allow() {
+		return http.StatusTooManyRequests, "rate limit exceeded for this API key"
+	}
+	return 0, ""
+}
+
+// checkSize enforces the key's ciphertext size limit.
+func (k *APIKey) checkSize(n int) (int, string) {
+	if p := k.Policy; p.MaxSize > 0 && n > p.MaxSize {
+		return http.StatusRequestEntityTooLarge,
+			fmt.Sprintf("ciphertext exceeds this key's limit (%d bytes)", p.MaxSize)
+	}
+	return 0, ""
+}
+
+// checkExpiry enforces the key's expiry limit on a unix expiry time.
+func (k *APIKey) checkExpiry(expiry int64) (int, string) {
+	if p := k.Policy; p.MaxExpirySeconds > 0 && expiry-time.Now().Unix() > p.MaxExpirySeconds {
+		return http.StatusForbidden,
+			fmt.Sprintf("expiry exceeds this key's limit (%d seconds)", p.MaxExpirySeconds)
+	}
+	return 0, ""
+}
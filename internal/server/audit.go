@@ -0,0 +1,80 @@
+package server
+
+import (
+	"log/slog"
+	"sync"
+
+	"github.com/yesahem/burnenv/internal/audit"
+)
+
+// AuditWriter appends every store lifecycle event to an audit log. Drop IDs
+// are hashed with HashID so entries can be correlated with access logs
+// without revealing retrievable IDs.
+//
+// Events are queued by Observe, which runs under the store lock, and
+// written in order by a separate goroutine, so a slow disk never blocks
+// requests. Write failures are logged rather than failing the request.
+type AuditWriter struct {
+	log    *audit.Log
+	logger *slog.Logger
+
+	mu     sync.Mutex
+	queue  []Event
+	closed bool
+	wake   chan struct{}
+	done   chan struct{}
+}
+
+// NewAuditWriter starts writing events to l. Call Close to flush.
+func NewAuditWriter(l *audit.Log, logger *slog.Logger) *AuditWriter {
+	w := &AuditWriter{log: l, logger: logger, wake: make(chan struct{}, 1), done: make(chan struct{})}
+	go w.run()
+	return w
+}
+
+// Observe queues ev; pass it to Store.AddObserver. Events after Close are
+// dropped.
+func (w *AuditWriter) Observe(ev Event) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	if w.closed {
+		return
+	}
+	w.queue = append(w.queue, ev)
+	select {
+	case w.wake <- struct{}{}:
+	default:
+	}
+}
+
+// Close writes the queued events and stops the writer. Stop the store
+// first so no more events arrive. The audit log itself stays open.
+func (w *AuditWriter) Close() {
+	w.mu.Lock()
+	if !w.closed {
+		w.closed = true
+		close(w.wake)
+	}
+	w.mu.Unlock()
+	<-w.done
+}
+
+func (w *AuditWriter) run() {
+	defer close(w.done)
+	for range w.wake {
+		w.flush()
+	}
+	w.flush()
+}
+
+func (w *AuditWriter) flush() {
+	w.mu.Lock()
+	batch := w.queue
+	w.queue = nil
+	w.mu.Unlock()
+	for _, ev := range batch {
+		if err := w.log.Record(ev.Kind.String(), HashID(ev.ID), ev.Key, ev.Time); err != nil {
+			w.logger.Error("audit log write failed", "error", err)
+		}
+	}
+}
@@ -0,0 +1,262 @@
+package server
+
+import (
+	"fmt"
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/spf13/pflag"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds per-deployment server settings.
+//
+// Every setting has one name used by all three sources:
+// flag --max-expiry, env BURNENV_MAX_EXPIRY, config file key max_expiry.
+// Precedence: defaults < config file < environment < flags.
+type Config struct {
+	Addr       string // host:port or unix:/path/to.sock
+	AdminAddr  string // Separate listener for /metrics; empty disables it
+	BaseURL    string
+	SocketMode string // Octal permissions for unix: sockets, e.g. "0660"
+
+	CORSOrigins []string // Browser origins allowed to call the API ("*" for any)
+
+	MaxRequestBodyBytes int64
+	MaxCiphertextLen    int
+	MinExpiry           time.Duration
+	MaxExpiry           time.Duration
+	MinMaxViews         int
+	MaxMaxViews         int
+
+	ShutdownGrace time.Duration // Max wait for in-flight requests on shutdown
+	DrainDelay    time.Duration // Time /readyz fails before the listener closes
+
+	LogFormat string // "text" or "json"
+	LogLevel  string // debug, info, warn, error
+	AuditLog  string // Hash-chained lifecycle log file ("" disables)
+
+	APIKeysFile string
+	PoWEnabled  bool
+	PoW         PoWConfig
+
+	CodeClaimRate int // Code claims per minute from one client address (0: unlimited)
+}
+
+// DefaultConfig returns the built-in defaults.
+func DefaultConfig() Config {
+	return Config{
+		Addr:                ":8080",
+		BaseURL:             "http://localhost:8080",
+		SocketMode:          "0660",
+		MaxRequestBodyBytes: DefaultMaxRequestBodyBytes,
+		MaxCiphertextLen:    DefaultMaxCiphertextLen,
+		MinExpiry:           DefaultMinExpirySeconds * time.Second,
+		MaxExpiry:           DefaultMaxExpirySeconds * time.Second,
+		MinMaxViews:         DefaultMinMaxViews,
+		MaxMaxViews:         DefaultMaxMaxViews,
+		ShutdownGrace:       15 * time.Second,
+		LogFormat:           "text",
+		LogLevel:            "info",
+		PoW: PoWConfig{
+			Difficulty:    18,
+			MaxDifficulty: 24,
+			RatePerMinute: 60,
+			Capacity:      10000,
+		},
+		CodeClaimRate: 10,
+	}
+}
+
+// BindFlags registers one flag per setting on fs, using c's current values
+// as defaults.
+func (c *Config) BindFlags(fs *pflag.FlagSet) {
+	fs.StringVarP(&c.Addr, "addr", "a", c.Addr, "Listen address (host:port or unix:/path.sock)")
+	fs.StringVar(&c.AdminAddr, "admin-addr", c.AdminAddr, "Admin listen address for /metrics (e.g. 127.0.0.1:9090); empty disables")
+	fs.StringVar(&c.SocketMode, "socket-mode", c.SocketMode, "Octal permissions for unix: sockets")
+	fs.StringSliceVar(&c.CORSOrigins, "cors-origins", c.CORSOrigins, "Comma-separated browser origins allowed to call the API (e.g. https://tools.example.com); * allows any")
+	fs.StringVar(&c.BaseURL, "base-url", c.BaseURL, "Base URL for generated links")
+	fs.Int64Var(&c.MaxRequestBodyBytes, "max-request-bytes", c.MaxRequestBodyBytes, "Maximum request body size in bytes")
+	fs.IntVar(&c.MaxCiphertextLen, "max-ciphertext-bytes", c.MaxCiphertextLen, "Maximum base64 ciphertext length in bytes")
+	fs.DurationVar(&c.MinExpiry, "min-expiry", c.MinExpiry, "Shortest allowed drop lifetime")
+	fs.DurationVar(&c.MaxExpiry, "max-expiry", c.MaxExpiry, "Longest allowed drop lifetime")
+	fs.IntVar(&c.MinMaxViews, "min-views", c.MinMaxViews, "Smallest allowed max_views")
+	fs.IntVar(&c.MaxMaxViews, "max-views", c.MaxMaxViews, "Largest allowed max_views")
+	fs.DurationVar(&c.ShutdownGrace, "shutdown-grace", c.ShutdownGrace, "Maximum time to let in-flight requests finish on SIGINT/SIGTERM")
+	fs.DurationVar(&c.DrainDelay, "drain-delay", c.DrainDelay, "Time /readyz reports draining before the listener stops accepting")
+	fs.StringVar(&c.LogFormat, "log-format", c.LogFormat, "Access log format: text or json")
+	fs.StringVar(&c.LogLevel, "log-level", c.LogLevel, "Log level: debug, info, warn or error")
+	fs.StringVar(&c.AuditLog, "audit-log", c.AuditLog, "Append a hash-chained lifecycle log to this file (see: burnenv audit verify)")
+	fs.StringVar(&c.APIKeysFile, "api-keys", c.APIKeysFile, "JSON file of hashed API keys allowed to create drops (see: burnenv apikey new)")
+	fs.BoolVar(&c.PoWEnabled, "pow", c.PoWEnabled, "Require a proof-of-work solution to create drops (anti-spam)")
+	fs.IntVar(&c.PoW.Difficulty, "pow-difficulty", c.PoW.Difficulty, "Base proof-of-work difficulty in leading zero bits")
+	fs.IntVar(&c.PoW.MaxDifficulty, "pow-max-difficulty", c.PoW.MaxDifficulty, "Maximum difficulty after load-based scaling")
+	fs.IntVar(&c.PoW.RatePerMinute, "pow-rate", c.PoW.RatePerMinute, "Creates per minute tolerated before difficulty rises")
+	fs.IntVar(&c.PoW.Capacity, "pow-capacity", c.PoW.Capacity, "Stored drops at which difficulty is at its utilization maximum")
+	fs.IntVar(&c.CodeClaimRate, "code-claim-rate", c.CodeClaimRate, "Short-code claims per minute allowed from one client address (0 disables the limit)")
+}
+
+// LoadConfig builds a Config from an optional YAML/TOML file, BURNENV_*
+// environment variables and the explicitly set flags in flags, then
+// validates it. lookupEnv is usually os.LookupEnv.
+func LoadConfig(path string, lookupEnv func(string) (string, bool), flags *pflag.FlagSet) (Config, error) {
+	cfg := DefaultConfig()
+	fs := pflag.NewFlagSet("config", pflag.ContinueOnError)
+	cfg.BindFlags(fs)
+
+	if path != "" {
+		values, err := readConfigFile(path)
+		if err != nil {
+			return cfg, err
+		}
+		keys := make([]string, 0, len(values))
+		for k := range values {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			name := strings.ReplaceAll(k, "_", "-")
+			if fs.Lookup(name) == nil {
+				return cfg, fmt.Errorf("config %s: unknown key %q", path, k)
+			}
+			value := fmt.Sprint(values[k])
+			if list, ok := values[k].([]interface{}); ok {
+				items := make([]string, len(list))
+				for i, v := range list {
+					items[i] = fmt.Sprint(v)
+				}
+				value = strings.Join(items, ",")
+			}
+			if err := fs.Set(name, value); err != nil {
+				return cfg, fmt.Errorf("config %s: %s: %w", path, k, err)
+			}
+		}
+	}
+
+	var err error
+	fs.VisitAll(func(f *pflag.Flag) {
+		env := "BURNENV_" + strings.ToUpper(strings.ReplaceAll(f.Name, "-", "_"))
+		if v, ok := lookupEnv(env); ok && err == nil {
+			if e := fs.Set(f.Name, v); e != nil {
+				err = fmt.Errorf("%s: %w", env, e)
+			}
+		}
+	})
+	if err != nil {
+		return cfg, err
+	}
+
+	if flags != nil {
+		flags.Visit(func(f *pflag.Flag) {
+			dst := fs.Lookup(f.Name)
+			if dst == nil || err != nil {
+				return
+			}
+			// Slice values stringify as "[a,b]"; copy the elements instead
+			if src, ok := f.Value.(pflag.SliceValue); ok {
+				err = dst.Value.(pflag.SliceValue).Replace(src.GetSlice())
+				return
+			}
+			err = fs.Set(f.Name, f.Value.String())
+		})
+		if err != nil {
+			return cfg, err
+		}
+	}
+
+	return cfg, cfg.Validate()
+}
+
+// readConfigFile decodes a flat YAML (.yaml/.yml) or TOML (.toml) file.
+func readConfigFile(path string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	values := map[string]interface{}{}
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, &values)
+	case ".toml":
+		err = toml.Unmarshal(data, &values)
+	default:
+		return nil, fmt.Errorf("config %s: unsupported format (use .yaml, .yml or .toml)", path)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("parse config %s: %w", path, err)
+	}
+	return values, nil
+}
+
+// Validate checks that the settings are usable.
+func (c Config) Validate() error {
+	if c.Addr == "" {
+		return fmt.Errorf("addr cannot be empty")
+	}
+	if c.AdminAddr != "" && c.AdminAddr == c.Addr {
+		return fmt.Errorf("admin-addr must differ from addr")
+	}
+	if _, err := c.socketMode(); err != nil {
+		return err
+	}
+	for _, o := range c.CORSOrigins {
+		if o == "*" {
+			continue
+		}
+		u, err := url.Parse(o)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" || (u.Path != "" && u.Path != "/") || u.RawQuery != "" {
+			return fmt.Errorf("cors-origins: %q must be an origin such as https://tools.example.com", o)
+		}
+		if u.Path == "/" {
+			return fmt.Errorf("cors-origins: %q must not end with a slash", o)
+		}
+	}
+	u, err := url.Parse(c.BaseURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return fmt.Errorf("base-url must be an absolute http(s) URL")
+	}
+	if c.MaxRequestBodyBytes <= 0 {
+		return fmt.Errorf("max-request-bytes must be positive")
+	}
+	if c.MaxCiphertextLen <= 0 || int64(c.MaxCiphertextLen) > c.MaxRequestBodyBytes {
+		return fmt.Errorf("max-ciphertext-bytes must be positive and at most max-request-bytes")
+	}
+	if c.MinExpiry < time.Second {
+		return fmt.Errorf("min-expiry must be at least 1s")
+	}
+	if c.MaxExpiry < c.MinExpiry {
+		return fmt.Errorf("max-expiry must not be shorter than min-expiry")
+	}
+	if c.ShutdownGrace < 0 || c.DrainDelay < 0 {
+		return fmt.Errorf("shutdown-grace and drain-delay cannot be negative")
+	}
+	if c.LogFormat != "text" && c.LogFormat != "json" {
+		return fmt.Errorf("log-format must be text or json")
+	}
+	if c.MinMaxViews < 1 {
+		return fmt.Errorf("min-views must be at least 1")
+	}
+	if c.MaxMaxViews < c.MinMaxViews {
+		return fmt.Errorf("max-views must not be smaller than min-views")
+	}
+	if c.CodeClaimRate < 0 {
+		return fmt.Errorf("code-claim-rate cannot be negative")
+	}
+	return nil
+}
+
+// socketMode parses SocketMode as octal permission bits.
+func (c Config) socketMode() (fs.FileMode, error) {
+	m, err := strconv.ParseUint(c.SocketMode, 8, 32)
+	if err != nil || m > 0777 {
+		return 0, fmt.Errorf("socket-mode must be octal permissions such as 0660")
+	}
+	return fs.FileMode(m), nil
+}
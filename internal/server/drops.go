@@ -0,0 +1,146 @@
+package server
+
+import (
+	"context"
+	"net/http"
+	"time"
+
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/pow"
+	"github.com/yesahem/burnenv/internal/version"
+)
+
+// apiError is a request failure, independent of the API that reports it.
+type apiError struct {
+	status int
+	code   apierr.Code
+	msg    string
+}
+
+// writeAPIError sends e as a JSON error response.
+func writeAPIError(w http.ResponseWriter, e *apiError) {
+	if e.code == apierr.Unauthorized {
+		w.Header().Set("WWW-Authenticate", `Bearer realm="burnenv"`)
+	}
+	writeError(w, e.status, e.code, e.msg)
+}
+
+// drops implements drop operations for both the JSON and the RPC API, so
+// they share the store, validation, API keys and proof-of-work.
+type drops struct {
+	store      *Store
+	cfg        Config
+	challenges *PoW     // nil: no proof-of-work
+	keys       *KeyRing // nil: anyone may create
+}
+
+// info describes the server's policy.
+func (d *drops) info() infoResponse {
+	features := []string{"drop", "revoke", "short_codes", "rpc"}
+	if d.challenges != nil {
+		features = append(features, "pow")
+	}
+	if d.keys != nil {
+		features = append(features, "api_keys")
+	}
+	return infoResponse{
+		Version:       version.Version,
+		KDFAlgorithms: AcceptedKDFAlgorithms,
+		Limits: infoLimits{
+			MaxRequestBytes:    d.cfg.MaxRequestBodyBytes,
+			MaxCiphertextBytes: d.cfg.MaxCiphertextLen,
+			MinExpirySeconds:   int64(d.cfg.MinExpiry / time.Second),
+			MaxExpirySeconds:   int64(d.cfg.MaxExpiry / time.Second),
+			MinMaxViews:        d.cfg.MinMaxViews,
+			MaxMaxViews:        d.cfg.MaxMaxViews,
+		},
+		Features: features,
+	}
+}
+
+// authorize checks the API key and proof-of-work headers of a create
+// request. Called before the body is read.
+func (d *drops) authorize(h http.Header) (*APIKey, *apiError) {
+	var key *APIKey
+	if d.keys != nil {
+		var ok bool
+		key, ok = d.keys.Authenticate(h.Get("Authorization"))
+		if !ok {
+			return nil, &apiError{http.StatusUnauthorized, apierr.Unauthorized, "valid API key required to create drops"}
+		}
+	}
+	// Anti-spam
+	if d.challenges != nil {
+		if err := d.challenges.Verify(h.Get(pow.HeaderChallenge), h.Get(pow.HeaderNonce)); err != nil {
+			return nil, &apiError{http.StatusForbidden, apierr.PoWFailed, err.Error()}
+		}
+	}
+	return key, nil
+}
+
+// create validates req and stores raw, the payload's JSON encoding.
+func (d *drops) create(req *dropCreateRequest, raw []byte, key *APIKey) (dropCreateResponse, *apiError) {
+	// Full server-side validation (size, expiry, max_views, required fields)
+	if status, msg := validateRequest(req, &d.cfg); status != 0 {
+		return dropCreateResponse{}, &apiError{status, apierr.ForStatus(status), msg}
+	}
+	var keyName string
+	if key != nil {
+		if status, msg := key.checkPolicy(req); status != 0 {
+			return dropCreateResponse{}, &apiError{status, apierr.ForStatus(status), msg}
+		}
+		keyName = key.Name
+	}
+
+	id := randomID()
+	d.store.Put(id, raw, req.MaxViews, time.Unix(req.Expiry, 0), keyName)
+	return dropCreateResponse{
+		ID:      id,
+		Link:    d.cfg.BaseURL + "/v1/drop/" + id,
+		WebLink: d.cfg.BaseURL + "/d/" + id,
+	}, nil
+}
+
+// errDropNotFound is returned for lookups of unknown or burned drops.
+var errDropNotFound = &apiError{http.StatusNotFound, apierr.NotFound, "🔥 Secret not found - it may have been burned or never existed"}
+
+// get retrieves a payload and consumes one view.
+func (d *drops) get(ctx context.Context, id string) ([]byte, *apiError) {
+	blob, reason := d.store.GetWithReason(id)
+	if blob != nil {
+		return blob, nil
+	}
+	switch reason {
+	case ReasonExpired:
+		return nil, &apiError{http.StatusGone, apierr.Expired, "🔥 Secret expired and was automatically burned"}
+	case ReasonMaxViews:
+		return nil, &apiError{http.StatusGone, apierr.Exhausted, "🔥 Secret already retrieved and burned (max views reached)"}
+	}
+	markNotFound(ctx)
+	return nil, errDropNotFound
+}
+
+// dropStatus is a drop's state as reported without consuming a view.
+type dropStatus struct {
+	ViewsRemaining int
+	MaxViews       int
+	Expiry         time.Time
+}
+
+// status looks up a drop without consuming a view.
+func (d *drops) status(ctx context.Context, id string) (dropStatus, *apiError) {
+	views, maxViews, expiry, ok := d.store.Status(id)
+	if !ok {
+		markNotFound(ctx)
+		return dropStatus{}, errDropNotFound
+	}
+	return dropStatus{views, maxViews, expiry}, nil
+}
+
+// revoke burns a drop without retrieving it.
+func (d *drops) revoke(id string) *apiError {
+	if !d.store.Delete(id) {
+		return &apiError{http.StatusNotFound, apierr.NotFound, "not found"}
+	}
+	return nil
+}
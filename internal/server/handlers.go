@@ -2,17 +2,27 @@ package server
 
 import (
 	"crypto/rand"
+	_ "embed"
 	"encoding/hex"
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/codes"
 )
 
 // Minimal JSON types for API - server does NOT parse secret contents.
 // Only validates structure enough to extract expiry/max_views for enforcement.
 
+// openAPI is the versioned contract of the /v1 API, served as-is.
+//
+//go:embed openapi.json
+var openAPI []byte
+
 type dropCreateRequest struct {
 	Ciphertext string `json:"ciphertext"`
 	Salt       string `json:"salt"`
@@ -23,17 +33,79 @@ type dropCreateRequest struct {
 		Memory    uint32 `json:"memory"`
 		Threads   uint8  `json:"threads"`
 	} `json:"kdf"`
-	Expiry   int64 `json:"expiry"`
-	MaxViews int   `json:"max_views"`
+	Expiry      int64  `json:"expiry"`
+	MaxViews    int    `json:"max_views"`
+	ContentType string `json:"content_type,omitempty"` // Authenticated by the client's AEAD; opaque here
 }
 
 type dropCreateResponse struct {
-	ID   string `json:"id"`
-	Link string `json:"link"`
+	ID      string `json:"id"`
+	Link    string `json:"link"`
+	WebLink string `json:"web_link"` // Browser retrieval page
+}
+
+// Short-code (send/receive) API types. The server only relays SPAKE2
+// messages and sealed blobs; it never sees the code words.
+
+type codeOpenRequest struct {
+	Pake   string `json:"pake"`
+	Expiry int64  `json:"expiry"`
+}
+
+type codeOpenResponse struct {
+	Channel int    `json:"channel"`
+	Expiry  int64  `json:"expiry"`
+	Token   string `json:"token"` // Sender's bearer token (codes.HeaderToken)
+}
+
+type codeClaimRequest struct {
+	Pake string `json:"pake"`
+}
+
+type codeClaimResponse struct {
+	Attempt int    `json:"attempt"`
+	Pake    string `json:"pake"`
+	Token   string `json:"token"` // Receiver's bearer token (codes.HeaderToken)
+}
+
+type codeStatusResponse struct {
+	Attempts []string `json:"attempts"`
+	Closed   bool     `json:"closed"`
+	ClosedBy int      `json:"closed_by"`       // Attempt that confirmed delivery
+	Proof    string   `json:"proof,omitempty"` // Its key confirmation
+}
+
+type codeCloseRequest struct {
+	Attempt int    `json:"attempt"`
+	Proof   string `json:"proof"`
+}
+
+type codeSealed struct {
+	IV         string `json:"iv"`
+	Ciphertext string `json:"ciphertext"`
+}
+
+// infoResponse advertises server policy so clients can adapt their limits.
+type infoResponse struct {
+	Version       string     `json:"version"`
+	KDFAlgorithms []string   `json:"kdf_algorithms"`
+	Limits        infoLimits `json:"limits"`
+	Features      []string   `json:"features"`
+}
+
+type infoLimits struct {
+	MaxRequestBytes    int64 `json:"max_request_bytes"`
+	MaxCiphertextBytes int   `json:"max_ciphertext_bytes"`
+	MinExpirySeconds   int64 `json:"min_expiry_seconds"`
+	MaxExpirySeconds   int64 `json:"max_expiry_seconds"`
+	MinMaxViews        int   `json:"min_max_views"`
+	MaxMaxViews        int   `json:"max_max_views"`
 }
 
 type errorResponse struct {
-	Error string `json:"error"`
+	Error     string      `json:"error"`
+	Code      apierr.Code `json:"code"`
+	RequestID string      `json:"request_id,omitempty"`
 }
 
 func randomID() string {
@@ -49,69 +121,104 @@ func writeJSON(w http.ResponseWriter, status int, v interface{}) {
 	json.NewEncoder(w).Encode(v)
 }
 
-// writeError sends an error response.
-func writeError(w http.ResponseWriter, status int, msg string) {
-	writeJSON(w, status, errorResponse{Error: msg})
+// writeError sends an error response, including the request ID set by
+// AccessLog so users can quote it in bug reports.
+func writeError(w http.ResponseWriter, status int, code apierr.Code, msg string) {
+	writeJSON(w, status, errorResponse{Error: msg, Code: code, RequestID: w.Header().Get(HeaderRequestID)})
+}
+
+// writeCodeError maps a nameplate lookup failure to an HTTP error.
+func writeCodeError(w http.ResponseWriter, reason NotFoundReason) {
+	switch reason {
+	case ReasonExpired:
+		writeError(w, http.StatusGone, apierr.Expired, "🔥 Code expired and was automatically burned")
+	case ReasonMaxAttempts:
+		writeError(w, http.StatusGone, apierr.Exhausted, "🔥 Too many attempts - code burned")
+	case ReasonMaxViews:
+		writeError(w, http.StatusGone, apierr.Exhausted, "🔥 Code already used and burned")
+	default:
+		writeError(w, http.StatusNotFound, apierr.NotFound, "🔥 Code not found - it may have been burned or never existed")
+	}
+}
+
+// channelParam parses the {channel} path value.
+func channelParam(r *http.Request) (int, bool) {
+	ch, err := strconv.Atoi(r.PathValue("channel"))
+	return ch, err == nil && ch > 0
 }
 
 // Handler returns the HTTP handler for the API.
-func Handler(store *Store, baseURL string) http.Handler {
+// challenges may be nil to disable the proof-of-work requirement.
+// keys may be nil to let anyone create drops; retrieval never needs a key.
+func Handler(store *Store, cfg Config, challenges *PoW, keys *KeyRing) http.Handler {
 	mux := http.NewServeMux()
 
+	d := &drops{store: store, cfg: cfg, challenges: challenges, keys: keys}
+	info := d.info()
+	claims := newClientLimiter(cfg.CodeClaimRate)
+
+	// RPC API (Connect, gRPC, gRPC-Web) sharing the drop logic below
+	mux.Handle(rpcHandler(d))
+	mux.HandleFunc("GET /v1/info", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, info)
+	})
+
+	mux.HandleFunc("GET /v1/openapi.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write(openAPI)
+	})
+
+	if challenges != nil {
+		mux.HandleFunc("GET /v1/challenge", func(w http.ResponseWriter, r *http.Request) {
+			writeJSON(w, http.StatusOK, challenges.Issue())
+		})
+	}
+
 	mux.HandleFunc("POST /v1/drop", func(w http.ResponseWriter, r *http.Request) {
+		// Authentication and anti-spam: checked before the body is read
+		key, apiErr := d.authorize(r.Header)
+		if apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+
 		// Limit request body size to prevent DoS
-		r.Body = http.MaxBytesReader(w, r.Body, MaxRequestBodyBytes)
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodyBytes)
 
 		// Server must never log request body
 		if r.Body == nil {
-			writeError(w, http.StatusBadRequest, "missing body")
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "missing body")
 			return
 		}
 		var raw json.RawMessage
 		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
 			if strings.Contains(err.Error(), "http: request body too large") {
-				writeError(w, http.StatusRequestEntityTooLarge,
-					fmt.Sprintf("request body exceeds %d MB limit", MaxRequestBodyBytes/(1024*1024)))
+				writeError(w, http.StatusRequestEntityTooLarge, apierr.TooLarge,
+					fmt.Sprintf("request body exceeds %d MB limit", cfg.MaxRequestBodyBytes/(1024*1024)))
 				return
 			}
-			writeError(w, http.StatusBadRequest, "invalid JSON")
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid JSON")
 			return
 		}
 		// Parse and validate payload
 		var req dropCreateRequest
 		if err := json.Unmarshal(raw, &req); err != nil {
-			writeError(w, http.StatusBadRequest, "invalid payload structure")
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid payload structure")
 			return
 		}
 
-		// Full server-side validation (size, expiry, max_views, required fields)
-		if status, msg := validateRequest(&req); status != 0 {
-			writeError(w, status, msg)
+		resp, apiErr := d.create(&req, raw, key)
+		if apiErr != nil {
+			writeAPIError(w, apiErr)
 			return
 		}
-
-		expiry := time.Unix(req.Expiry, 0)
-		id := randomID()
-		store.Put(id, raw, req.MaxViews, expiry)
-
-		link := baseURL + "/v1/drop/" + id
-		writeJSON(w, http.StatusCreated, dropCreateResponse{ID: id, Link: link})
+		writeJSON(w, http.StatusCreated, resp)
 	})
 
 	mux.HandleFunc("GET /v1/drop/{id}", func(w http.ResponseWriter, r *http.Request) {
-		id := r.PathValue("id")
-		blob, reason := store.GetWithReason(id)
-		if blob == nil {
-			switch reason {
-			case ReasonExpired:
-				writeError(w, http.StatusGone, "🔥 Secret expired and was automatically burned")
-			case ReasonMaxViews:
-				writeError(w, http.StatusGone, "🔥 Secret already retrieved and burned (max views reached)")
-			case ReasonNotFound:
-				writeError(w, http.StatusNotFound, "🔥 Secret not found - it may have been burned or never existed")
-			default:
-				writeError(w, http.StatusNotFound, "Secret not found or expired")
-			}
+		blob, apiErr := d.get(r.Context(), r.PathValue("id"))
+		if apiErr != nil {
+			writeAPIError(w, apiErr)
 			return
 		}
 		w.Header().Set("Content-Type", "application/json")
@@ -120,14 +227,182 @@ func Handler(store *Store, baseURL string) http.Handler {
 	})
 
 	mux.HandleFunc("DELETE /v1/drop/{id}", func(w http.ResponseWriter, r *http.Request) {
-		id := r.PathValue("id")
-		ok := store.Delete(id)
-		if !ok {
-			writeError(w, http.StatusNotFound, "not found")
+		if apiErr := d.revoke(r.PathValue("id")); apiErr != nil {
+			writeAPIError(w, apiErr)
 			return
 		}
 		writeJSON(w, http.StatusOK, map[string]string{"status": "revoked"})
 	})
 
+	// --- Short codes (burnenv send / burnenv receive) ---
+
+	mux.HandleFunc("POST /v1/code", func(w http.ResponseWriter, r *http.Request) {
+		// Same API key and anti-spam checks as POST /v1/drop
+		key, apiErr := d.authorize(r.Header)
+		if apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodyBytes)
+		var req codeOpenRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid JSON")
+			return
+		}
+		if status, msg := validatePake(req.Pake); status != 0 {
+			writeError(w, status, apierr.ForStatus(status), msg)
+			return
+		}
+		if status, msg := validateExpiry(req.Expiry, &cfg); status != 0 {
+			writeError(w, status, apierr.ForStatus(status), msg)
+			return
+		}
+		if key != nil {
+			if status, msg := key.checkCodePolicy(req.Expiry); status != 0 {
+				writeError(w, status, apierr.ForStatus(status), msg)
+				return
+			}
+		}
+		ch, token, ok := store.OpenNameplate(req.Pake, time.Unix(req.Expiry, 0))
+		if !ok {
+			writeError(w, http.StatusTooManyRequests, apierr.RateLimited, "too many open codes - try again later")
+			return
+		}
+		writeJSON(w, http.StatusCreated, codeOpenResponse{Channel: ch, Expiry: req.Expiry, Token: token})
+	})
+
+	mux.HandleFunc("GET /v1/code/{channel}", func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := channelParam(r)
+		if !ok {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid channel")
+			return
+		}
+		st, found, reason := store.NameplateStatus(ch, r.Header.Get(codes.HeaderToken))
+		if !found {
+			writeCodeError(w, reason)
+			return
+		}
+		writeJSON(w, http.StatusOK, codeStatusResponse{Attempts: st.Attempts, Closed: st.Closed, ClosedBy: st.ClosedBy, Proof: st.Proof})
+	})
+
+	mux.HandleFunc("DELETE /v1/code/{channel}", func(w http.ResponseWriter, r *http.Request) {
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodyBytes)
+		ch, ok := channelParam(r)
+		if !ok {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid channel")
+			return
+		}
+		var req codeCloseRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid JSON")
+			return
+		}
+		if req.Proof == "" || len(req.Proof) > MaxProofLen {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "missing or oversized proof")
+			return
+		}
+		if !store.CloseNameplate(ch, req.Attempt, r.Header.Get(codes.HeaderToken), req.Proof) {
+			writeError(w, http.StatusNotFound, apierr.NotFound, "not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "closed"})
+	})
+
+	mux.HandleFunc("POST /v1/code/{channel}/claim", func(w http.ResponseWriter, r *http.Request) {
+		// Each claim is a guess that brings the code closer to burning, so
+		// claims are gated like creates and limited per client
+		if !claims.allow(clientAddr(r)) {
+			writeError(w, http.StatusTooManyRequests, apierr.RateLimited, "too many code claims - try again later")
+			return
+		}
+		if _, apiErr := d.authorize(r.Header); apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodyBytes)
+		ch, ok := channelParam(r)
+		if !ok {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid channel")
+			return
+		}
+		var req codeClaimRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid JSON")
+			return
+		}
+		if status, msg := validatePake(req.Pake); status != 0 {
+			writeError(w, status, apierr.ForStatus(status), msg)
+			return
+		}
+		attempt, pakeA, token, reason := store.ClaimNameplate(ch, req.Pake, MaxCodeAttempts)
+		if pakeA == "" {
+			writeCodeError(w, reason)
+			return
+		}
+		writeJSON(w, http.StatusOK, codeClaimResponse{Attempt: attempt, Pake: pakeA, Token: token})
+	})
+
+	mux.HandleFunc("PUT /v1/code/{channel}/claim/{attempt}", func(w http.ResponseWriter, r *http.Request) {
+		// Sealing stores ciphertext, so it is gated like creating a drop
+		key, apiErr := d.authorize(r.Header)
+		if apiErr != nil {
+			writeAPIError(w, apiErr)
+			return
+		}
+		r.Body = http.MaxBytesReader(w, r.Body, cfg.MaxRequestBodyBytes)
+		ch, ok := channelParam(r)
+		attempt, err := strconv.Atoi(r.PathValue("attempt"))
+		if !ok || err != nil {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid channel or attempt")
+			return
+		}
+		var raw json.RawMessage
+		if err := json.NewDecoder(r.Body).Decode(&raw); err != nil {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid JSON")
+			return
+		}
+		var sealed codeSealed
+		if err := json.Unmarshal(raw, &sealed); err != nil || sealed.IV == "" || sealed.Ciphertext == "" {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid payload structure")
+			return
+		}
+		if len(sealed.Ciphertext) > cfg.MaxCiphertextLen || len(sealed.IV) > MaxIVLen {
+			writeError(w, http.StatusRequestEntityTooLarge, apierr.TooLarge, "sealed payload exceeds maximum size")
+			return
+		}
+		if key != nil {
+			if status, msg := key.checkSize(len(sealed.Ciphertext)); status != 0 {
+				writeError(w, status, apierr.ForStatus(status), msg)
+				return
+			}
+		}
+		if !store.SealAttempt(ch, attempt, r.Header.Get(codes.HeaderToken), raw) {
+			writeError(w, http.StatusNotFound, apierr.NotFound, "not found")
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "sealed"})
+	})
+
+	mux.HandleFunc("GET /v1/code/{channel}/claim/{attempt}", func(w http.ResponseWriter, r *http.Request) {
+		ch, ok := channelParam(r)
+		attempt, err := strconv.Atoi(r.PathValue("attempt"))
+		if !ok || err != nil {
+			writeError(w, http.StatusBadRequest, apierr.BadRequest, "invalid channel or attempt")
+			return
+		}
+		sealed, found, reason := store.SealedAttempt(ch, attempt)
+		if !found {
+			writeCodeError(w, reason)
+			return
+		}
+		if sealed == nil {
+			writeJSON(w, http.StatusAccepted, map[string]string{"status": "pending"})
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusOK)
+		w.Write(sealed)
+	})
+
 	return mux
 }
@@ -0,0 +1,36 @@
+package server
+
+import (
+	"net/http"
+	"sync/atomic"
+)
+
+// Health serves liveness and readiness probes.
+// Readiness fails once draining starts so load balancers stop sending new
+// requests while in-flight ones finish.
+type Health struct {
+	draining atomic.Bool
+}
+
+// SetDraining marks the server as shutting down; /readyz then returns 503.
+func (h *Health) SetDraining() {
+	h.draining.Store(true)
+}
+
+// Routes serves GET /healthz and GET /readyz and passes everything else
+// to next.
+func (h *Health) Routes(next http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /healthz", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ok"})
+	})
+	mux.HandleFunc("GET /readyz", func(w http.ResponseWriter, r *http.Request) {
+		if h.draining.Load() {
+			writeJSON(w, http.StatusServiceUnavailable, map[string]string{"status": "draining"})
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]string{"status": "ready"})
+	})
+	mux.Handle("/", next)
+	return mux
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// newTestStore returns a store that is stopped when the test ends.
+func newTestStore(t *testing.T) *Store {
+	t.Helper()
+	s := NewStore()
+	t.Cleanup(s.Stop)
+	return s
+}
+
+// testDrop returns a valid create request. The server never decodes the
+// ciphertext, so it can carry a marker to look for in logs.
+func testDrop(ciphertext string) map[string]any {
+	return map[string]any{
+		"ciphertext": ciphertext,
+		"salt":       "c2FsdHNhbHRzYWx0c2FsdA==",
+		"iv":         "aXZpdml2aXZpdml2",
+		"kdf":        map[string]any{"algorithm": "argon2id", "time": 3, "memory": 65536, "threads": 4},
+		"expiry":     time.Now().Add(10 * time.Minute).Unix(),
+		"max_views":  1,
+	}
+}
+
+// do sends a JSON request through h and returns the recorded response.
+func do(t *testing.T, h http.Handler, method, target string, body any, header http.Header) *httptest.ResponseRecorder {
+	t.Helper()
+	var buf bytes.Buffer
+	if body != nil {
+		if err := json.NewEncoder(&buf).Encode(body); err != nil {
+			t.Fatal(err)
+		}
+	}
+	req := httptest.NewRequest(method, target, &buf)
+	for k, v := range header {
+		req.Header[k] = v
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	return rec
+}
+
+// decode unmarshals a recorded JSON response into v.
+func decode(t *testing.T, rec *httptest.ResponseRecorder, v any) {
+	t.Helper()
+	if err := json.Unmarshal(rec.Body.Bytes(), v); err != nil {
+		t.Fatalf("decode %q: %v", rec.Body.String(), err)
+	}
+}
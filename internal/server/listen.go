@@ -0,0 +1,150 @@
+package server
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// UnixPrefix marks a listen address as a Unix domain socket path,
+// e.g. "unix:/run/burnenv.sock".
+const UnixPrefix = "unix:"
+
+// listenFDsStart is the first file descriptor passed by systemd (SD_LISTEN_FDS_START).
+const listenFDsStart = 3
+
+// Listeners are the sockets runServe serves on. Admin is nil when the
+// admin listener is disabled.
+type Listeners struct {
+	Public net.Listener
+	Admin  net.Listener
+}
+
+// Listen opens the public and admin listeners. Sockets passed by systemd
+// socket activation (LISTEN_FDS) take precedence over cfg.Addr: the socket
+// named "admin" in LISTEN_FDNAMES (FileDescriptorName=admin) becomes the
+// admin listener and the first other socket the public one. Otherwise
+// addresses are TCP host:port or unix:/path.
+func Listen(cfg Config) (*Listeners, error) {
+	mode, err := cfg.socketMode()
+	if err != nil {
+		return nil, err
+	}
+	activated, err := activationListeners()
+	if err != nil {
+		return nil, err
+	}
+
+	var ls Listeners
+	for _, a := range activated {
+		if a.name == "admin" {
+			ls.Admin = a.l
+		} else if ls.Public == nil {
+			ls.Public = a.l
+		} else {
+			a.l.Close() // Extra sockets are not used
+		}
+	}
+	if ls.Public == nil {
+		if ls.Public, err = listenAddr(cfg.Addr, mode); err != nil {
+			return nil, err
+		}
+	}
+	if ls.Admin == nil && cfg.AdminAddr != "" {
+		if ls.Admin, err = listenAddr(cfg.AdminAddr, mode); err != nil {
+			ls.Public.Close()
+			return nil, err
+		}
+	}
+	return &ls, nil
+}
+
+// Describe returns a human-readable form of a listener address.
+func Describe(l net.Listener) string {
+	if l.Addr().Network() == "unix" {
+		return UnixPrefix + l.Addr().String()
+	}
+	return l.Addr().String()
+}
+
+// listenAddr listens on a TCP address or a unix: socket path. A stale
+// socket file left by an unclean exit is replaced; any other file is not.
+func listenAddr(addr string, mode fs.FileMode) (net.Listener, error) {
+	path, ok := strings.CutPrefix(addr, UnixPrefix)
+	if !ok {
+		return net.Listen("tcp", addr)
+	}
+	if path == "" {
+		return nil, fmt.Errorf("listen %s: empty socket path", addr)
+	}
+	if fi, err := os.Lstat(path); err == nil {
+		if fi.Mode().Type() != fs.ModeSocket {
+			return nil, fmt.Errorf("listen %s: file exists and is not a socket", addr)
+		}
+		if err := os.Remove(path); err != nil {
+			return nil, fmt.Errorf("remove stale socket: %w", err)
+		}
+	}
+	l, err := listenUnix(path, mode)
+	if err != nil {
+		return nil, err
+	}
+	// Exact mode, whatever the platform's umask support
+	if err := os.Chmod(path, mode); err != nil {
+		l.Close()
+		return nil, fmt.Errorf("chmod socket: %w", err)
+	}
+	return l, nil
+}
+
+// activatedListener is a socket passed by systemd with its LISTEN_FDNAMES
+// entry ("fd<N>" when unnamed).
+type activatedListener struct {
+	name string
+	l    net.Listener
+}
+
+// activationListeners returns the sockets passed via systemd socket
+// activation, in order. The variables are unset so child processes do not
+// inherit them.
+func activationListeners() ([]activatedListener, error) {
+	defer func() {
+		os.Unsetenv("LISTEN_PID")
+		os.Unsetenv("LISTEN_FDS")
+		os.Unsetenv("LISTEN_FDNAMES")
+	}()
+	pid, err := strconv.Atoi(os.Getenv("LISTEN_PID"))
+	if err != nil || pid != os.Getpid() {
+		return nil, nil
+	}
+	n, err := strconv.Atoi(os.Getenv("LISTEN_FDS"))
+	if err != nil || n < 1 {
+		return nil, errors.New("socket activation: invalid LISTEN_FDS")
+	}
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+
+	listeners := make([]activatedListener, 0, n)
+	for i := 0; i < n; i++ {
+		fd := listenFDsStart + i
+		name := "fd" + strconv.Itoa(fd)
+		if i < len(names) && names[i] != "" {
+			name = names[i]
+		}
+		// FileListener dups the descriptor (close-on-exec); release the original
+		f := os.NewFile(uintptr(fd), name)
+		l, err := net.FileListener(f)
+		f.Close()
+		if err != nil {
+			for _, a := range listeners {
+				a.l.Close()
+			}
+			return nil, fmt.Errorf("socket activation: fd %d: %w", fd, err)
+		}
+		listeners = append(listeners, activatedListener{name: name, l: l})
+	}
+	return listeners, nil
+}
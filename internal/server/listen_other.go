@@ -0,0 +1,14 @@
+//go:build !unix
+
+package server
+
+import (
+	"io/fs"
+	"net"
+)
+
+// listenUnix binds a unix socket. There is no umask here; listenAddr sets
+// mode right after.
+func listenUnix(path string, mode fs.FileMode) (net.Listener, error) {
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,20 @@
+//go:build unix
+
+package server
+
+import (
+	"io/fs"
+	"net"
+	"syscall"
+)
+
+// listenUnix binds a unix socket with mode already applied: the umask is
+// narrowed around the bind, so the socket is never reachable with looser
+// permissions, not even until the chmod that follows. The umask is
+// process-wide, but this only runs at startup and can only make files
+// created meanwhile more private.
+func listenUnix(path string, mode fs.FileMode) (net.Listener, error) {
+	old := syscall.Umask(int(^mode & fs.ModePerm))
+	defer syscall.Umask(old)
+	return net.Listen("unix", path)
+}
@@ -0,0 +1,60 @@
+package server
+
+import (
+	"bytes"
+	"net/http"
+	"strings"
+	"testing"
+)
+
+// TestAccessLogOmitsSecrets checks that request bodies, query strings and
+// raw drop IDs never reach the log, in either format, and that drops are
+// logged only by their hashed ID.
+func TestAccessLogOmitsSecrets(t *testing.T) {
+	const (
+		bodyMarker  = "Qk9EWU1BUktFUg" // Ciphertext sent in the body
+		queryMarker = "querymarker"
+	)
+	for _, format := range []string{"json", "text"} {
+		t.Run(format, func(t *testing.T) {
+			var logs bytes.Buffer
+			logger, err := NewLogger(&logs, format, "debug")
+			if err != nil {
+				t.Fatal(err)
+			}
+			h := AccessLog(logger, Handler(newTestStore(t), DefaultConfig(), nil, nil))
+
+			rec := do(t, h, "POST", "/v1/drop?token="+queryMarker, testDrop(bodyMarker), nil)
+			if rec.Code != http.StatusCreated {
+				t.Fatalf("create: status %d: %s", rec.Code, rec.Body)
+			}
+			var created dropCreateResponse
+			decode(t, rec, &created)
+
+			for _, r := range []struct{ method, target string }{
+				{"GET", "/v1/drop/" + created.ID + "?key=" + queryMarker},
+				{"GET", "/v1/drop/" + created.ID}, // Already burned: 404
+				{"DELETE", "/v1/drop/" + created.ID + "?x=" + queryMarker},
+			} {
+				do(t, h, r.method, r.target, nil, nil)
+			}
+
+			out := logs.String()
+			if n := strings.Count(out, "\n"); n != 4 {
+				t.Fatalf("got %d log lines, want 4:\n%s", n, out)
+			}
+			for name, secret := range map[string]string{
+				"body":    bodyMarker,
+				"query":   queryMarker,
+				"drop ID": created.ID,
+			} {
+				if strings.Contains(out, secret) {
+					t.Errorf("log contains the %s %q:\n%s", name, secret, out)
+				}
+			}
+			if n := strings.Count(out, HashID(created.ID)); n != 3 {
+				t.Errorf("hashed drop ID logged %d times, want 3:\n%s", n, out)
+			}
+		})
+	}
+}
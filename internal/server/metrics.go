@@ -0,0 +1,205 @@
+package server
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// latencyBuckets are the request-duration histogram bounds in seconds.
+var latencyBuckets = []float64{0.005, 0.01, 0.025, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10}
+
+// Metrics collects Prometheus-style counters, gauges and histograms.
+// Labels are limited to route patterns (e.g. "GET /v1/drop/{id}"),
+// status codes and event kinds, so drop IDs can never become labels.
+type Metrics struct {
+	store *Store
+
+	mu       sync.Mutex
+	events   map[EventKind]uint64
+	notFound uint64
+	requests map[requestKey]uint64
+	latency  map[string]*histogram
+}
+
+type requestKey struct {
+	route string
+	code  int
+}
+
+type histogram struct {
+	counts []uint64 // Per bucket (non-cumulative), plus +Inf at the end
+	sum    float64
+	total  uint64
+}
+
+// NewMetrics creates a collector and subscribes it to store events.
+func NewMetrics(store *Store) *Metrics {
+	m := &Metrics{
+		store:    store,
+		events:   make(map[EventKind]uint64),
+		requests: make(map[requestKey]uint64),
+		latency:  make(map[string]*histogram),
+	}
+	store.AddObserver(m.observe)
+	return m
+}
+
+func (m *Metrics) observe(ev Event) {
+	m.mu.Lock()
+	m.events[ev.Kind]++
+	m.mu.Unlock()
+}
+
+// statusRecorder captures the response status for middleware.
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(code int) {
+	if r.status == 0 {
+		r.status = code
+	}
+	r.ResponseWriter.WriteHeader(code)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	return r.ResponseWriter.Write(b)
+}
+
+// Flush supports streaming responses (RPC server streams).
+func (r *statusRecorder) Flush() {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	http.NewResponseController(r.ResponseWriter).Flush()
+}
+
+// Unwrap exposes the underlying writer to http.ResponseController.
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+// routeLabel returns the matched mux pattern, never the raw path.
+func routeLabel(r *http.Request) string {
+	if r.Pattern == "" {
+		return "unmatched"
+	}
+	return r.Pattern
+}
+
+// notFoundKey is the context key of the flag markNotFound sets.
+type notFoundKey struct{}
+
+// markNotFound records that the request looked up an unknown or burned drop.
+// The JSON and RPC APIs both report through it, so
+// burnenv_drops_not_found_total counts misses whatever the protocol.
+func markNotFound(ctx context.Context) {
+	if flag, ok := ctx.Value(notFoundKey{}).(*atomic.Bool); ok {
+		flag.Store(true)
+	}
+}
+
+// Middleware records request counts and latency per route.
+func (m *Metrics) Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w}
+		var missed atomic.Bool
+		r = r.WithContext(context.WithValue(r.Context(), notFoundKey{}, &missed))
+		next.ServeHTTP(rec, r) // Sets r.Pattern for routeLabel
+		if rec.status == 0 {
+			rec.status = http.StatusOK
+		}
+		route := routeLabel(r)
+		elapsed := time.Since(start).Seconds()
+
+		m.mu.Lock()
+		defer m.mu.Unlock()
+		m.requests[requestKey{route, rec.status}]++
+		if missed.Load() {
+			m.notFound++
+		}
+		h := m.latency[route]
+		if h == nil {
+			h = &histogram{counts: make([]uint64, len(latencyBuckets)+1)}
+			m.latency[route] = h
+		}
+		i := sort.SearchFloat64s(latencyBuckets, elapsed)
+		h.counts[i]++
+		h.sum += elapsed
+		h.total++
+	})
+}
+
+// Handler serves the metrics in the Prometheus text exposition format.
+func (m *Metrics) Handler() http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		var b strings.Builder
+		m.write(&b)
+		w.Write([]byte(b.String()))
+	})
+}
+
+func (m *Metrics) write(b *strings.Builder) {
+	entries, bytes := m.store.Stats()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	counter := func(name, help string, v uint64) {
+		fmt.Fprintf(b, "# HELP %s %s\n# TYPE %s counter\n%s %d\n", name, help, name, name, v)
+	}
+	counter("burnenv_drops_created_total", "Drops created.", m.events[EventCreated])
+	counter("burnenv_drops_retrieved_total", "Successful drop retrievals (views consumed).", m.events[EventRetrieved])
+	counter("burnenv_drops_burned_total", "Drops destroyed after their last allowed view.", m.events[EventBurned])
+	counter("burnenv_drops_expired_total", "Drops that expired before all views were used.", m.events[EventExpired])
+	counter("burnenv_drops_revoked_total", "Drops revoked via DELETE.", m.events[EventRevoked])
+	counter("burnenv_drops_not_found_total", "Lookups of unknown or already burned drops (HTTP 404 or RPC NotFound).", m.notFound)
+
+	fmt.Fprintf(b, "# HELP burnenv_store_entries Drops currently stored.\n# TYPE burnenv_store_entries gauge\nburnenv_store_entries %d\n", entries)
+	fmt.Fprintf(b, "# HELP burnenv_store_bytes Ciphertext bytes currently stored.\n# TYPE burnenv_store_bytes gauge\nburnenv_store_bytes %d\n", bytes)
+
+	keys := make([]requestKey, 0, len(m.requests))
+	for k := range m.requests {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].route != keys[j].route {
+			return keys[i].route < keys[j].route
+		}
+		return keys[i].code < keys[j].code
+	})
+	b.WriteString("# HELP burnenv_http_requests_total HTTP requests by route and status.\n# TYPE burnenv_http_requests_total counter\n")
+	for _, k := range keys {
+		fmt.Fprintf(b, "burnenv_http_requests_total{route=%q,code=\"%d\"} %d\n", k.route, k.code, m.requests[k])
+	}
+
+	routes := make([]string, 0, len(m.latency))
+	for r := range m.latency {
+		routes = append(routes, r)
+	}
+	sort.Strings(routes)
+	b.WriteString("# HELP burnenv_http_request_duration_seconds HTTP request latency by route.\n# TYPE burnenv_http_request_duration_seconds histogram\n")
+	for _, route := range routes {
+		h := m.latency[route]
+		var cum uint64
+		for i, le := range latencyBuckets {
+			cum += h.counts[i]
+			fmt.Fprintf(b, "burnenv_http_request_duration_seconds_bucket{route=%q,le=\"%g\"} %d\n", route, le, cum)
+		}
+		fmt.Fprintf(b, "burnenv_http_request_duration_seconds_bucket{route=%q,le=\"+Inf\"} %d\n", route, h.total)
+		fmt.Fprintf(b, "burnenv_http_request_duration_seconds_sum{route=%q} %g\n", route, h.sum)
+		fmt.Fprintf(b, "burnenv_http_request_duration_seconds_count{route=%q} %d\n", route, h.total)
+	}
+}
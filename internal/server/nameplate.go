@@ -0,0 +1,171 @@
+package server
+
+import (
+	"crypto/subtle"
+	"time"
+)
+
+// nameplate is a short-code rendezvous channel for burnenv send/receive.
+// The server relays SPAKE2 messages and sealed blobs between the two sides;
+// it never learns the code words or the session key.
+type nameplate struct {
+	PakeA    string // Sender's SPAKE2 message (base64)
+	Token    string // Sender's bearer token for status and sealing
+	Attempts []*codeAttempt
+	Expiry   time.Time
+	Closed   bool   // Receiver confirmed delivery
+	ClosedBy int    // Attempt that confirmed delivery
+	Proof    string // Receiver's key confirmation, checked by the sender
+}
+
+// codeAttempt is one receiver's claim on a nameplate.
+type codeAttempt struct {
+	PakeB  string // Receiver's SPAKE2 message (base64)
+	Token  string // Receiver's bearer token for confirming delivery
+	Sealed []byte // Raw JSON of the sealed secret; nil until the sender uploads
+}
+
+// NameplateState is what the sender sees when polling a nameplate.
+type NameplateState struct {
+	Attempts []string // Receivers' SPAKE2 messages in claim order
+	Closed   bool
+	ClosedBy int
+	Proof    string
+}
+
+// tokenEqual compares a presented bearer token with the stored one.
+func tokenEqual(want, got string) bool {
+	return want != "" && subtle.ConstantTimeCompare([]byte(want), []byte(got)) == 1
+}
+
+// OpenNameplate allocates the lowest free channel number and returns it
+// with the sender's token. Small numbers keep codes short enough to read
+// over a call. ok is false when MaxOpenCodes channels are already open.
+func (s *Store) OpenNameplate(pakeA string, expiry time.Time) (ch int, token string, ok bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if len(s.nameplates) >= MaxOpenCodes {
+		return 0, "", false
+	}
+	ch = 1
+	for {
+		if _, used := s.nameplates[ch]; !used {
+			break
+		}
+		ch++
+	}
+	token = randomID()
+	s.nameplates[ch] = &nameplate{PakeA: pakeA, Token: token, Expiry: expiry}
+	return ch, token, true
+}
+
+// lookupNameplate returns a live nameplate, deleting it if expired.
+// Caller must hold s.mu.
+func (s *Store) lookupNameplate(ch int) (*nameplate, NotFoundReason) {
+	np, ok := s.nameplates[ch]
+	if !ok {
+		return nil, ReasonNotFound
+	}
+	if time.Now().After(np.Expiry) {
+		delete(s.nameplates, ch)
+		return nil, ReasonExpired
+	}
+	return np, ReasonNotFound
+}
+
+// ClaimNameplate records a receiver's SPAKE2 message and returns the attempt
+// number, the sender's message and the receiver's token. Once maxAttempts
+// claims have been made, the next one burns the nameplate so a wrong guess
+// cannot be retried forever.
+func (s *Store) ClaimNameplate(ch int, pakeB string, maxAttempts int) (attempt int, pakeA, token string, reason NotFoundReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	np, reason := s.lookupNameplate(ch)
+	if np == nil {
+		return 0, "", "", reason
+	}
+	if np.Closed {
+		delete(s.nameplates, ch)
+		return 0, "", "", ReasonMaxViews
+	}
+	if len(np.Attempts) >= maxAttempts {
+		delete(s.nameplates, ch)
+		return 0, "", "", ReasonMaxAttempts
+	}
+	token = randomID()
+	np.Attempts = append(np.Attempts, &codeAttempt{PakeB: pakeB, Token: token})
+	return len(np.Attempts) - 1, np.PakeA, token, ReasonNotFound
+}
+
+// NameplateStatus returns the receivers' SPAKE2 messages and whether
+// delivery was confirmed, to the holder of the sender's token. A closed
+// nameplate is deleted once the sender has seen it.
+func (s *Store) NameplateStatus(ch int, token string) (st NameplateState, found bool, reason NotFoundReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	np, reason := s.lookupNameplate(ch)
+	if np == nil || !tokenEqual(np.Token, token) {
+		return NameplateState{}, false, reason
+	}
+	st = NameplateState{Attempts: make([]string, len(np.Attempts)), Closed: np.Closed, ClosedBy: np.ClosedBy, Proof: np.Proof}
+	for i, a := range np.Attempts {
+		st.Attempts[i] = a.PakeB
+	}
+	if np.Closed {
+		delete(s.nameplates, ch)
+	}
+	return st, true, ReasonNotFound
+}
+
+// SealAttempt stores the sender's sealed secret for one attempt. token must
+// be the sender's.
+func (s *Store) SealAttempt(ch, attempt int, token string, sealed []byte) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	np, _ := s.lookupNameplate(ch)
+	if np == nil || np.Closed || !tokenEqual(np.Token, token) || attempt < 0 || attempt >= len(np.Attempts) {
+		return false
+	}
+	np.Attempts[attempt].Sealed = sealed
+	return true
+}
+
+// SealedAttempt returns the sealed secret for one attempt, or nil while the
+// sender has not uploaded it yet (found is true in that case).
+func (s *Store) SealedAttempt(ch, attempt int) (sealed []byte, found bool, reason NotFoundReason) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	np, reason := s.lookupNameplate(ch)
+	if np == nil {
+		return nil, false, reason
+	}
+	if attempt < 0 || attempt >= len(np.Attempts) {
+		return nil, false, ReasonNotFound
+	}
+	return np.Attempts[attempt].Sealed, true, ReasonNotFound
+}
+
+// CloseNameplate marks delivery as confirmed so the sender can stop waiting.
+// token must be the receiver's for attempt, and the attempt must have been
+// sealed. proof is passed on to the sender, who checks it against the
+// session key, so a receiver who claimed with the wrong code cannot fake
+// delivery.
+func (s *Store) CloseNameplate(ch, attempt int, token, proof string) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	np, _ := s.lookupNameplate(ch)
+	if np == nil || np.Closed || attempt < 0 || attempt >= len(np.Attempts) {
+		return false
+	}
+	if a := np.Attempts[attempt]; !tokenEqual(a.Token, token) || a.Sealed == nil {
+		return false
+	}
+	np.Closed = true
+	np.ClosedBy = attempt
+	np.Proof = proof
+	np.PakeA = ""
+	for _, a := range np.Attempts {
+		a.Sealed = nil
+	}
+	return true
+}
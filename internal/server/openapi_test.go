@@ -0,0 +1,316 @@
+package server
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"slices"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/yesahem/burnenv/internal/codes"
+	"github.com/yesahem/burnenv/internal/pow"
+)
+
+// contract validates responses against the embedded OpenAPI document. It
+// understands the subset of JSON Schema the document uses ($ref, type,
+// required, properties, items, enum, minimum, maxLength, format byte).
+// Properties missing from a schema are errors, so the document cannot
+// fall behind the handlers.
+type contract struct {
+	doc struct {
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas   map[string]map[string]any `json:"schemas"`
+			Responses map[string]map[string]any `json:"responses"`
+		} `json:"components"`
+	}
+	seen map[string]bool // "method path" of every operation exercised
+}
+
+func loadContract(t *testing.T) *contract {
+	t.Helper()
+	c := &contract{seen: map[string]bool{}}
+	if err := json.Unmarshal(openAPI, &c.doc); err != nil {
+		t.Fatalf("openapi.json: %v", err)
+	}
+	return c
+}
+
+// operation finds the documented operation for a request path.
+func (c *contract) operation(method, path string) (string, map[string]any, bool) {
+	for tmpl, item := range c.doc.Paths {
+		if !matchPath(tmpl, path) {
+			continue
+		}
+		var op map[string]any
+		if err := json.Unmarshal(item[strings.ToLower(method)], &op); err != nil {
+			return tmpl, nil, false
+		}
+		return tmpl, op, true
+	}
+	return "", nil, false
+}
+
+// matchPath reports whether path fits a template like /v1/drop/{id}.
+func matchPath(tmpl, path string) bool {
+	want, got := strings.Split(tmpl, "/"), strings.Split(path, "/")
+	if len(want) != len(got) {
+		return false
+	}
+	for i, seg := range want {
+		if got[i] != seg && !(strings.HasPrefix(seg, "{") && got[i] != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// check validates one recorded response to method path.
+func (c *contract) check(t *testing.T, method, path string, rec *httptest.ResponseRecorder) {
+	t.Helper()
+	tmpl, op, ok := c.operation(method, path)
+	if !ok {
+		t.Errorf("%s %s: not documented", method, path)
+		return
+	}
+	c.seen[method+" "+tmpl] = true
+	responses, _ := op["responses"].(map[string]any)
+	resp, ok := responses[strconv.Itoa(rec.Code)].(map[string]any)
+	if !ok {
+		t.Errorf("%s %s: status %d not documented (body %s)", method, path, rec.Code, rec.Body)
+		return
+	}
+	if ref, ok := resp["$ref"].(string); ok {
+		resp = c.doc.Components.Responses[strings.TrimPrefix(ref, "#/components/responses/")]
+	}
+	content, _ := resp["content"].(map[string]any)
+	media, ok := content["application/json"].(map[string]any)
+	if !ok {
+		return // No body documented
+	}
+	if ct := rec.Header().Get("Content-Type"); ct != "application/json" {
+		t.Errorf("%s %s: Content-Type %q, want application/json", method, path, ct)
+	}
+	var body any
+	if err := json.Unmarshal(rec.Body.Bytes(), &body); err != nil {
+		t.Errorf("%s %s: body is not JSON: %v", method, path, err)
+		return
+	}
+	if err := c.validate(media["schema"].(map[string]any), body, "body"); err != nil {
+		t.Errorf("%s %s %d: %v\n%s", method, path, rec.Code, err, rec.Body)
+	}
+}
+
+func (c *contract) validate(s map[string]any, v any, at string) error {
+	if ref, ok := s["$ref"].(string); ok {
+		s = c.doc.Components.Schemas[strings.TrimPrefix(ref, "#/components/schemas/")]
+	}
+	if enum, ok := s["enum"].([]any); ok && !slices.Contains(enum, v) {
+		return fmt.Errorf("%s: %v not in %v", at, v, enum)
+	}
+	switch s["type"] {
+	case "object":
+		obj, ok := v.(map[string]any)
+		if !ok {
+			return fmt.Errorf("%s: want object, got %T", at, v)
+		}
+		props, _ := s["properties"].(map[string]any)
+		required, _ := s["required"].([]any)
+		for _, r := range required {
+			if _, ok := obj[r.(string)]; !ok {
+				return fmt.Errorf("%s: missing required %q", at, r)
+			}
+		}
+		if props == nil {
+			return nil // Free-form object
+		}
+		for k, val := range obj {
+			p, ok := props[k].(map[string]any)
+			if !ok {
+				return fmt.Errorf("%s: undocumented property %q", at, k)
+			}
+			if err := c.validate(p, val, at+"."+k); err != nil {
+				return err
+			}
+		}
+	case "array":
+		arr, ok := v.([]any)
+		if !ok {
+			return fmt.Errorf("%s: want array, got %T", at, v)
+		}
+		for i, item := range arr {
+			if err := c.validate(s["items"].(map[string]any), item, fmt.Sprintf("%s[%d]", at, i)); err != nil {
+				return err
+			}
+		}
+	case "string":
+		str, ok := v.(string)
+		if !ok {
+			return fmt.Errorf("%s: want string, got %T", at, v)
+		}
+		if max, ok := s["maxLength"].(float64); ok && len(str) > int(max) {
+			return fmt.Errorf("%s: longer than %v", at, max)
+		}
+		if s["format"] == "byte" {
+			if _, err := base64.StdEncoding.DecodeString(str); err != nil {
+				return fmt.Errorf("%s: not base64: %v", at, err)
+			}
+		}
+	case "integer":
+		n, ok := v.(float64)
+		if !ok || n != float64(int64(n)) {
+			return fmt.Errorf("%s: want integer, got %v", at, v)
+		}
+		if min, ok := s["minimum"].(float64); ok && n < min {
+			return fmt.Errorf("%s: %v below minimum %v", at, n, min)
+		}
+	case "boolean":
+		if _, ok := v.(bool); !ok {
+			return fmt.Errorf("%s: want boolean, got %T", at, v)
+		}
+	}
+	return nil
+}
+
+// TestOpenAPIContract drives every /v1 route, including its error paths,
+// and checks each status and body against openapi.json.
+func TestOpenAPIContract(t *testing.T) {
+	c := loadContract(t)
+	cfg := DefaultConfig()
+	cfg.MaxCiphertextLen = 1024
+	store := newTestStore(t)
+	h := Handler(store, cfg, nil, nil)
+
+	// call sends a request and checks the response against the contract.
+	call := func(h http.Handler, method, path string, body any, header http.Header, want int) *httptest.ResponseRecorder {
+		t.Helper()
+		rec := do(t, h, method, path, body, header)
+		if rec.Code != want {
+			t.Errorf("%s %s: status %d, want %d: %s", method, path, rec.Code, want, rec.Body)
+		}
+		c.check(t, method, path, rec)
+		return rec
+	}
+
+	call(h, "GET", "/v1/info", nil, nil, http.StatusOK)
+	call(h, "GET", "/v1/openapi.json", nil, nil, http.StatusOK)
+	call(h, "GET", "/v1/challenge", nil, nil, http.StatusNotFound)
+
+	// Drops
+	var created dropCreateResponse
+	decode(t, call(h, "POST", "/v1/drop", testDrop("Y2lwaGVydGV4dA=="), nil, http.StatusCreated), &created)
+	call(h, "POST", "/v1/drop", "not an object", nil, http.StatusBadRequest)
+	call(h, "POST", "/v1/drop", testDrop(strings.Repeat("A", 2048)), nil, http.StatusRequestEntityTooLarge)
+	call(h, "GET", "/v1/drop/"+created.ID, nil, nil, http.StatusOK)
+	call(h, "GET", "/v1/drop/"+created.ID, nil, nil, http.StatusNotFound)
+	store.Put("expired", []byte(`{}`), 1, time.Now().Add(-time.Second), "")
+	call(h, "GET", "/v1/drop/expired", nil, nil, http.StatusGone)
+	decode(t, call(h, "POST", "/v1/drop", testDrop("Y2lwaGVydGV4dA=="), nil, http.StatusCreated), &created)
+	call(h, "DELETE", "/v1/drop/"+created.ID, nil, nil, http.StatusOK)
+	call(h, "DELETE", "/v1/drop/"+created.ID, nil, nil, http.StatusNotFound)
+
+	// Short codes
+	pake := map[string]any{"pake": "cGFrZQ=="}
+	var opened codeOpenResponse
+	decode(t, call(h, "POST", "/v1/code", map[string]any{"pake": "cGFrZQ==", "expiry": time.Now().Add(5 * time.Minute).Unix()}, nil, http.StatusCreated), &opened)
+	call(h, "POST", "/v1/code", map[string]any{"expiry": 1}, nil, http.StatusBadRequest)
+	ch := fmt.Sprintf("/v1/code/%d", opened.Channel)
+	sender := http.Header{http.CanonicalHeaderKey(codes.HeaderToken): {opened.Token}}
+	call(h, "GET", ch, nil, sender, http.StatusOK)
+	call(h, "GET", ch, nil, nil, http.StatusNotFound) // No token
+	call(h, "GET", "/v1/code/x", nil, sender, http.StatusBadRequest)
+
+	var claim codeClaimResponse
+	decode(t, call(h, "POST", ch+"/claim", pake, nil, http.StatusOK), &claim)
+	call(h, "POST", ch+"/claim", map[string]any{}, nil, http.StatusBadRequest)
+	call(h, "POST", "/v1/code/999/claim", pake, nil, http.StatusNotFound)
+	receiver := http.Header{http.CanonicalHeaderKey(codes.HeaderToken): {claim.Token}}
+	attempt := fmt.Sprintf("%s/claim/%d", ch, claim.Attempt)
+
+	call(h, "GET", attempt, nil, nil, http.StatusAccepted)
+	sealed := map[string]any{"iv": "aXZpdml2aXZpdml2", "ciphertext": "c2VhbGVk"}
+	call(h, "PUT", attempt, sealed, sender, http.StatusOK)
+	call(h, "PUT", attempt, sealed, receiver, http.StatusNotFound) // Not the sender
+	call(h, "PUT", attempt, map[string]any{"iv": "aXY="}, sender, http.StatusBadRequest)
+	call(h, "PUT", attempt, map[string]any{"iv": "aXY=", "ciphertext": strings.Repeat("A", 2048)}, sender, http.StatusRequestEntityTooLarge)
+	call(h, "GET", attempt, nil, nil, http.StatusOK)
+	call(h, "GET", ch+"/claim/x", nil, nil, http.StatusBadRequest)
+	call(h, "GET", ch+"/claim/9", nil, nil, http.StatusNotFound)
+
+	proof := map[string]any{"attempt": claim.Attempt, "proof": "cHJvb2Y="}
+	call(h, "DELETE", ch, map[string]any{"attempt": claim.Attempt}, receiver, http.StatusBadRequest)
+	call(h, "DELETE", ch, proof, sender, http.StatusNotFound) // Not the receiver
+	call(h, "DELETE", ch, proof, receiver, http.StatusOK)
+	call(h, "GET", ch, nil, sender, http.StatusOK) // Sees the close, then it is gone
+	call(h, "GET", ch, nil, sender, http.StatusNotFound)
+
+	// Too many attempts burn the code
+	decode(t, call(h, "POST", "/v1/code", map[string]any{"pake": "cGFrZQ==", "expiry": time.Now().Add(5 * time.Minute).Unix()}, nil, http.StatusCreated), &opened)
+	ch = fmt.Sprintf("/v1/code/%d", opened.Channel)
+	for range MaxCodeAttempts {
+		call(h, "POST", ch+"/claim", pake, nil, http.StatusOK)
+	}
+	call(h, "POST", ch+"/claim", pake, nil, http.StatusGone)
+
+	// API keys and proof of work
+	keysFile := filepath.Join(t.TempDir(), "keys.json")
+	os.WriteFile(keysFile, []byte(`{"keys": [{"name": "ci", "sha256": "`+HashAPIKey("secret-key")+`", "policy": {"rate_per_minute": 2}}]}`), 0600)
+	keys, err := LoadKeyRing(keysFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	challenges, err := NewPoW(store, PoWConfig{Difficulty: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	gated := Handler(store, cfg, challenges, keys)
+	solved := func() http.Header {
+		var ch challengeResponse
+		decode(t, call(gated, "GET", "/v1/challenge", nil, nil, http.StatusOK), &ch)
+		h := http.Header{"Authorization": {"Bearer secret-key"}}
+		h.Set(pow.HeaderChallenge, ch.Challenge)
+		h.Set(pow.HeaderNonce, pow.Solve(ch.Challenge, ch.Difficulty))
+		return h
+	}
+	codeBody := map[string]any{"pake": "cGFrZQ==", "expiry": time.Now().Add(5 * time.Minute).Unix()}
+	call(gated, "POST", "/v1/drop", testDrop("Y2lwaGVydGV4dA=="), nil, http.StatusUnauthorized)
+	call(gated, "POST", "/v1/code", codeBody, nil, http.StatusUnauthorized)
+	call(gated, "POST", "/v1/drop", testDrop("Y2lwaGVydGV4dA=="), http.Header{"Authorization": {"Bearer secret-key"}}, http.StatusForbidden)
+	call(gated, "POST", "/v1/code", codeBody, http.Header{"Authorization": {"Bearer secret-key"}}, http.StatusForbidden)
+	call(gated, "POST", "/v1/drop", testDrop("Y2lwaGVydGV4dA=="), solved(), http.StatusCreated)
+	decode(t, call(gated, "POST", "/v1/code", codeBody, solved(), http.StatusCreated), &opened)
+	call(gated, "POST", "/v1/drop", testDrop("Y2lwaGVydGV4dA=="), solved(), http.StatusTooManyRequests)
+	call(gated, "POST", "/v1/code", codeBody, solved(), http.StatusTooManyRequests)
+	ch = fmt.Sprintf("/v1/code/%d", opened.Channel)
+	call(gated, "POST", ch+"/claim", pake, nil, http.StatusUnauthorized)
+	call(gated, "POST", ch+"/claim", pake, http.Header{"Authorization": {"Bearer secret-key"}}, http.StatusForbidden)
+	decode(t, call(gated, "POST", ch+"/claim", pake, solved(), http.StatusOK), &claim)
+	call(gated, "PUT", fmt.Sprintf("%s/claim/%d", ch, claim.Attempt), sealed, http.Header{http.CanonicalHeaderKey(codes.HeaderToken): {opened.Token}}, http.StatusUnauthorized)
+
+	// Claims are limited per client
+	cfg.CodeClaimRate = 1
+	limited := Handler(store, cfg, nil, nil)
+	decode(t, call(limited, "POST", "/v1/code", codeBody, nil, http.StatusCreated), &opened)
+	ch = fmt.Sprintf("/v1/code/%d", opened.Channel)
+	call(limited, "POST", ch+"/claim", pake, nil, http.StatusOK)
+	call(limited, "POST", ch+"/claim", pake, nil, http.StatusTooManyRequests)
+
+	// Every documented operation must have been exercised
+	for tmpl, item := range c.doc.Paths {
+		for method := range item {
+			if method == "parameters" {
+				continue
+			}
+			if key := strings.ToUpper(method) + " " + tmpl; !c.seen[key] {
+				t.Errorf("%s: not exercised", key)
+			}
+		}
+	}
+}
@@ -0,0 +1,170 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/bits"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/yesahem/burnenv/internal/pow"
+)
+
+// challengeTTL is how long an issued challenge may be redeemed.
+const challengeTTL = 2 * time.Minute
+
+// PoWConfig tunes the anti-spam proof of work.
+type PoWConfig struct {
+	Difficulty    int // Base difficulty in leading zero bits
+	MaxDifficulty int // Upper bound after auto-scaling
+	RatePerMinute int // Creates per minute tolerated before difficulty rises
+	Capacity      int // Stored entries considered "full" for utilization scaling
+}
+
+// PoW issues and verifies stateless, HMAC-signed hashcash challenges.
+// Difficulty rises by one bit per doubling of the create rate above
+// RatePerMinute, and by up to four bits as the store fills.
+type PoW struct {
+	cfg    PoWConfig
+	store  *Store
+	secret []byte
+
+	mu      sync.Mutex
+	buckets [60]int   // Creates per second, ring indexed by unix second
+	stamps  [60]int64 // Unix second each bucket belongs to
+	spent   map[string]time.Time
+}
+
+type challengeResponse struct {
+	Challenge  string `json:"challenge"`
+	Difficulty int    `json:"difficulty"`
+	Expires    int64  `json:"expires"`
+}
+
+// NewPoW creates a challenge issuer bound to store for utilization scaling.
+func NewPoW(store *Store, cfg PoWConfig) (*PoW, error) {
+	if cfg.Difficulty < 1 || cfg.Difficulty > pow.MaxDifficulty {
+		return nil, fmt.Errorf("pow difficulty must be between 1 and %d", pow.MaxDifficulty)
+	}
+	if cfg.MaxDifficulty < cfg.Difficulty {
+		cfg.MaxDifficulty = cfg.Difficulty
+	}
+	if cfg.MaxDifficulty > pow.MaxDifficulty {
+		cfg.MaxDifficulty = pow.MaxDifficulty
+	}
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+	return &PoW{cfg: cfg, store: store, secret: secret, spent: make(map[string]time.Time)}, nil
+}
+
+// Difficulty returns the current difficulty after load-based scaling.
+func (p *PoW) Difficulty() int {
+	d := p.cfg.Difficulty
+	if rate := p.rate(); p.cfg.RatePerMinute > 0 && rate > p.cfg.RatePerMinute {
+		d += bits.Len(uint(rate / p.cfg.RatePerMinute))
+	}
+	if p.cfg.Capacity > 0 {
+		switch used := float64(p.store.Len()) / float64(p.cfg.Capacity); {
+		case used >= 0.9:
+			d += 4
+		case used >= 0.75:
+			d += 2
+		case used >= 0.5:
+			d++
+		}
+	}
+	if d > p.cfg.MaxDifficulty {
+		d = p.cfg.MaxDifficulty
+	}
+	return d
+}
+
+// Issue returns a signed challenge at the current difficulty.
+func (p *PoW) Issue() challengeResponse {
+	nonce := make([]byte, 16)
+	rand.Read(nonce)
+	d := p.Difficulty()
+	exp := time.Now().Add(challengeTTL).Unix()
+	body := fmt.Sprintf("v1.%d.%d.%s", exp, d, hex.EncodeToString(nonce))
+	return challengeResponse{
+		Challenge:  body + "." + p.sign(body),
+		Difficulty: d,
+		Expires:    exp,
+	}
+}
+
+// Verify checks a challenge solution and marks the challenge as spent.
+func (p *PoW) Verify(challenge, nonce string) error {
+	if challenge == "" || nonce == "" {
+		return errors.New("proof-of-work required (GET /v1/challenge)")
+	}
+	i := strings.LastIndexByte(challenge, '.')
+	if i < 0 || !hmac.Equal([]byte(challenge[i+1:]), []byte(p.sign(challenge[:i]))) {
+		return errors.New("invalid proof-of-work challenge")
+	}
+	parts := strings.Split(challenge[:i], ".")
+	if len(parts) != 4 {
+		return errors.New("invalid proof-of-work challenge")
+	}
+	exp, _ := strconv.ParseInt(parts[1], 10, 64)
+	d, _ := strconv.Atoi(parts[2])
+	if time.Now().Unix() > exp {
+		return errors.New("proof-of-work challenge expired")
+	}
+	if !pow.Check(challenge, nonce, d) {
+		return errors.New("invalid proof-of-work solution")
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now()
+	for c, until := range p.spent {
+		if now.After(until) {
+			delete(p.spent, c)
+		}
+	}
+	if _, used := p.spent[challenge]; used {
+		return errors.New("proof-of-work challenge already used")
+	}
+	p.spent[challenge] = time.Unix(exp, 0)
+	p.recordLocked(now.Unix())
+	return nil
+}
+
+func (p *PoW) sign(body string) string {
+	mac := hmac.New(sha256.New, p.secret)
+	mac.Write([]byte(body))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// recordLocked counts one create in the current second. Caller holds p.mu.
+func (p *PoW) recordLocked(sec int64) {
+	i := sec % int64(len(p.buckets))
+	if p.stamps[i] != sec {
+		p.stamps[i] = sec
+		p.buckets[i] = 0
+	}
+	p.buckets[i]++
+}
+
+// rate returns creates over the last minute.
+func (p *PoW) rate() int {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	now := time.Now().Unix()
+	total := 0
+	for i, sec := range p.stamps {
+		if now-sec < int64(len(p.buckets)) {
+			total += p.buckets[i]
+		}
+	}
+	return total
+}
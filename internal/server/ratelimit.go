@@ -0,0 +1,72 @@
+package server
+
+import (
+	"net"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// clientLimiter is a token bucket per client address. It holds at most a
+// minute's worth of tokens, and buckets that have refilled are dropped so
+// idle clients cost nothing.
+type clientLimiter struct {
+	rate int // Tokens per minute; 0 disables the limit
+
+	mu      sync.Mutex
+	buckets map[string]*bucket
+	swept   time.Time
+}
+
+type bucket struct {
+	tokens float64
+	last   time.Time
+}
+
+func newClientLimiter(ratePerMinute int) *clientLimiter {
+	return &clientLimiter{rate: ratePerMinute, buckets: make(map[string]*bucket)}
+}
+
+// allow takes one token from client's bucket.
+func (l *clientLimiter) allow(client string) bool {
+	if l.rate <= 0 {
+		return true
+	}
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	now := time.Now()
+	if now.Sub(l.swept) > time.Minute {
+		for c, b := range l.buckets {
+			if l.refill(b, now) >= float64(l.rate) {
+				delete(l.buckets, c)
+			}
+		}
+		l.swept = now
+	}
+	b, ok := l.buckets[client]
+	if !ok {
+		b = &bucket{tokens: float64(l.rate), last: now}
+		l.buckets[client] = b
+	}
+	b.tokens = l.refill(b, now)
+	b.last = now
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// refill returns b's tokens at now, capped at one minute's worth.
+func (l *clientLimiter) refill(b *bucket, now time.Time) float64 {
+	return min(b.tokens+now.Sub(b.last).Minutes()*float64(l.rate), float64(l.rate))
+}
+
+// clientAddr identifies the client of r by its IP address. Requests from a
+// unix socket, or through a proxy, share one address.
+func clientAddr(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
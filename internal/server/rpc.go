@@ -0,0 +1,246 @@
+package server
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"sync"
+
+	"connectrpc.com/connect"
+	burnenvv1 "github.com/yesahem/burnenv/gen/burnenv/v1"
+	"github.com/yesahem/burnenv/gen/burnenv/v1/burnenvv1connect"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// HeaderErrorCode carries the apierr code of RPC errors, matching the
+// "code" field of JSON error responses.
+const HeaderErrorCode = "Burnenv-Error-Code"
+
+// rpcService implements the DropService RPC API (Connect, gRPC, gRPC-Web)
+// on top of the same drops logic as the JSON API.
+type rpcService struct {
+	d       *drops
+	watches *watchHub
+}
+
+var _ burnenvv1connect.DropServiceHandler = (*rpcService)(nil)
+
+// rpcHandler returns the mount path and handler of the RPC API.
+func rpcHandler(d *drops) (string, http.Handler) {
+	hub := &watchHub{subs: make(map[string]map[chan Event]struct{})}
+	d.store.AddObserver(hub.observe)
+	return burnenvv1connect.NewDropServiceHandler(&rpcService{d: d, watches: hub},
+		connect.WithReadMaxBytes(int(min(d.cfg.MaxRequestBodyBytes, math.MaxInt32))))
+}
+
+func (s *rpcService) GetInfo(ctx context.Context, req *connect.Request[burnenvv1.GetInfoRequest]) (*connect.Response[burnenvv1.GetInfoResponse], error) {
+	info := s.d.info()
+	return connect.NewResponse(&burnenvv1.GetInfoResponse{
+		Version:       info.Version,
+		KdfAlgorithms: info.KDFAlgorithms,
+		Limits: &burnenvv1.Limits{
+			MaxRequestBytes:    info.Limits.MaxRequestBytes,
+			MaxCiphertextBytes: int64(info.Limits.MaxCiphertextBytes),
+			MinExpirySeconds:   info.Limits.MinExpirySeconds,
+			MaxExpirySeconds:   info.Limits.MaxExpirySeconds,
+			MinMaxViews:        int32(info.Limits.MinMaxViews),
+			MaxMaxViews:        int32(info.Limits.MaxMaxViews),
+		},
+		Features: info.Features,
+	}), nil
+}
+
+func (s *rpcService) CreateDrop(ctx context.Context, req *connect.Request[burnenvv1.CreateDropRequest]) (*connect.Response[burnenvv1.CreateDropResponse], error) {
+	key, apiErr := s.d.authorize(req.Header())
+	if apiErr != nil {
+		return nil, rpcError(apiErr)
+	}
+	p := req.Msg.GetPayload()
+	if p == nil || p.GetKdf() == nil {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("missing payload or kdf"))
+	}
+	if p.GetKdf().GetThreads() > math.MaxUint8 {
+		return nil, connect.NewError(connect.CodeInvalidArgument, errors.New("kdf threads out of range"))
+	}
+
+	// Stored as the JSON API's payload, so either API can retrieve it
+	var dr dropCreateRequest
+	dr.Ciphertext = base64.StdEncoding.EncodeToString(p.GetCiphertext())
+	dr.Salt = base64.StdEncoding.EncodeToString(p.GetSalt())
+	dr.IV = base64.StdEncoding.EncodeToString(p.GetIv())
+	dr.KDF.Algorithm = p.GetKdf().GetAlgorithm()
+	dr.KDF.Time = p.GetKdf().GetTime()
+	dr.KDF.Memory = p.GetKdf().GetMemory()
+	dr.KDF.Threads = uint8(p.GetKdf().GetThreads())
+	dr.Expiry = p.GetExpiry()
+	dr.MaxViews = int(p.GetMaxViews())
+	dr.ContentType = p.GetContentType()
+	raw, err := json.Marshal(&dr)
+	if err != nil {
+		return nil, connect.NewError(connect.CodeInternal, err)
+	}
+
+	resp, apiErr := s.d.create(&dr, raw, key)
+	if apiErr != nil {
+		return nil, rpcError(apiErr)
+	}
+	return connect.NewResponse(&burnenvv1.CreateDropResponse{Id: resp.ID, Link: resp.Link, WebLink: resp.WebLink}), nil
+}
+
+func (s *rpcService) GetDrop(ctx context.Context, req *connect.Request[burnenvv1.GetDropRequest]) (*connect.Response[burnenvv1.GetDropResponse], error) {
+	blob, apiErr := s.d.get(ctx, req.Msg.GetId())
+	if apiErr != nil {
+		return nil, rpcError(apiErr)
+	}
+	var dr dropCreateRequest
+	if err := json.Unmarshal(blob, &dr); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("stored payload is not valid JSON"))
+	}
+	p := &burnenvv1.EncryptedPayload{
+		Kdf: &burnenvv1.KDFParams{
+			Algorithm: dr.KDF.Algorithm,
+			Time:      dr.KDF.Time,
+			Memory:    dr.KDF.Memory,
+			Threads:   uint32(dr.KDF.Threads),
+		},
+		Expiry:      dr.Expiry,
+		MaxViews:    int32(dr.MaxViews),
+		ContentType: dr.ContentType,
+	}
+	var err1, err2, err3 error
+	p.Ciphertext, err1 = base64.StdEncoding.DecodeString(dr.Ciphertext)
+	p.Salt, err2 = base64.StdEncoding.DecodeString(dr.Salt)
+	p.Iv, err3 = base64.StdEncoding.DecodeString(dr.IV)
+	if err := errors.Join(err1, err2, err3); err != nil {
+		return nil, connect.NewError(connect.CodeInternal, errors.New("stored payload is not valid base64"))
+	}
+	return connect.NewResponse(&burnenvv1.GetDropResponse{Payload: p}), nil
+}
+
+func (s *rpcService) GetDropStatus(ctx context.Context, req *connect.Request[burnenvv1.GetDropStatusRequest]) (*connect.Response[burnenvv1.GetDropStatusResponse], error) {
+	st, apiErr := s.d.status(ctx, req.Msg.GetId())
+	if apiErr != nil {
+		return nil, rpcError(apiErr)
+	}
+	return connect.NewResponse(&burnenvv1.GetDropStatusResponse{
+		ViewsRemaining: int32(st.ViewsRemaining),
+		MaxViews:       int32(st.MaxViews),
+		Expiry:         st.Expiry.Unix(),
+	}), nil
+}
+
+func (s *rpcService) RevokeDrop(ctx context.Context, req *connect.Request[burnenvv1.RevokeDropRequest]) (*connect.Response[burnenvv1.RevokeDropResponse], error) {
+	if apiErr := s.d.revoke(req.Msg.GetId()); apiErr != nil {
+		return nil, rpcError(apiErr)
+	}
+	return connect.NewResponse(&burnenvv1.RevokeDropResponse{}), nil
+}
+
+func (s *rpcService) WatchDrop(ctx context.Context, req *connect.Request[burnenvv1.WatchDropRequest], stream *connect.ServerStream[burnenvv1.DropEvent]) error {
+	id := req.Msg.GetId()
+	// Subscribe before checking existence so no terminal event is missed
+	events, cancel := s.watches.subscribe(id)
+	defer cancel()
+	if _, apiErr := s.d.status(ctx, id); apiErr != nil {
+		return rpcError(apiErr)
+	}
+	// Send headers now so clients see the stream open before the first event
+	if err := stream.Send(nil); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case ev := <-events:
+			kind, terminal := rpcEventKind(ev.Kind)
+			if kind == burnenvv1.DropEvent_KIND_UNSPECIFIED {
+				continue
+			}
+			if err := stream.Send(&burnenvv1.DropEvent{Kind: kind, Time: timestamppb.New(ev.Time)}); err != nil {
+				return err
+			}
+			if terminal {
+				return nil
+			}
+		}
+	}
+}
+
+// rpcEventKind maps a store event to its RPC kind; terminal events end a watch.
+func rpcEventKind(k EventKind) (kind burnenvv1.DropEvent_Kind, terminal bool) {
+	switch k {
+	case EventRetrieved:
+		return burnenvv1.DropEvent_KIND_RETRIEVED, false
+	case EventBurned:
+		return burnenvv1.DropEvent_KIND_BURNED, true
+	case EventExpired:
+		return burnenvv1.DropEvent_KIND_EXPIRED, true
+	case EventRevoked:
+		return burnenvv1.DropEvent_KIND_REVOKED, true
+	}
+	return burnenvv1.DropEvent_KIND_UNSPECIFIED, false
+}
+
+// rpcError converts an apiError to a Connect error, keeping the stable code
+// in the Burnenv-Error-Code metadata.
+func rpcError(e *apiError) error {
+	code := connect.CodeInternal
+	switch e.status {
+	case http.StatusBadRequest:
+		code = connect.CodeInvalidArgument
+	case http.StatusUnauthorized:
+		code = connect.CodeUnauthenticated
+	case http.StatusForbidden:
+		code = connect.CodePermissionDenied
+	case http.StatusNotFound:
+		code = connect.CodeNotFound
+	case http.StatusGone:
+		code = connect.CodeFailedPrecondition
+	case http.StatusRequestEntityTooLarge, http.StatusTooManyRequests:
+		code = connect.CodeResourceExhausted
+	}
+	err := connect.NewError(code, errors.New(e.msg))
+	err.Meta().Set(HeaderErrorCode, string(e.code))
+	return err
+}
+
+// watchHub fans store events out to WatchDrop streams by drop ID.
+type watchHub struct {
+	mu   sync.Mutex
+	subs map[string]map[chan Event]struct{}
+}
+
+// observe is a store observer; it runs under the store lock, so it never blocks.
+func (h *watchHub) observe(ev Event) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for ch := range h.subs[ev.ID] {
+		select {
+		case ch <- ev:
+		default: // Slow watcher: drop the event rather than stall the store
+		}
+	}
+}
+
+// subscribe returns a channel of events for id and a function to stop.
+func (h *watchHub) subscribe(id string) (<-chan Event, func()) {
+	ch := make(chan Event, 8)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.subs[id] == nil {
+		h.subs[id] = make(map[chan Event]struct{})
+	}
+	h.subs[id][ch] = struct{}{}
+	return ch, func() {
+		h.mu.Lock()
+		defer h.mu.Unlock()
+		delete(h.subs[id], ch)
+		if len(h.subs[id]) == 0 {
+			delete(h.subs, id)
+		}
+	}
+}
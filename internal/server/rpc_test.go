@@ -0,0 +1,272 @@
+package server
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"connectrpc.com/connect"
+	burnenvv1 "github.com/yesahem/burnenv/gen/burnenv/v1"
+	"github.com/yesahem/burnenv/gen/burnenv/v1/burnenvv1connect"
+	"github.com/yesahem/burnenv/internal/apierr"
+)
+
+// memListener is an in-memory net.Listener, like gRPC's bufconn: servers
+// and clients talk over net.Pipe without touching the network.
+type memListener struct {
+	conns chan net.Conn
+	done  chan struct{}
+	once  sync.Once
+}
+
+func newMemListener() *memListener {
+	return &memListener{conns: make(chan net.Conn), done: make(chan struct{})}
+}
+
+func (l *memListener) Accept() (net.Conn, error) {
+	select {
+	case c := <-l.conns:
+		return c, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	}
+}
+
+func (l *memListener) Close() error {
+	l.once.Do(func() { close(l.done) })
+	return nil
+}
+
+func (l *memListener) Addr() net.Addr { return memAddr{} }
+
+// DialContext connects a new client to the listener.
+func (l *memListener) DialContext(ctx context.Context, network, addr string) (net.Conn, error) {
+	server, client := net.Pipe()
+	select {
+	case l.conns <- server:
+		return client, nil
+	case <-l.done:
+		return nil, net.ErrClosed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+type memAddr struct{}
+
+func (memAddr) Network() string { return "mem" }
+func (memAddr) String() string  { return "mem" }
+
+// rpcClients serves h on an in-memory listener, as cmd/serve does (HTTP/1
+// and cleartext HTTP/2), and returns a client per RPC protocol.
+func rpcClients(t *testing.T, h http.Handler) map[string]burnenvv1connect.DropServiceClient {
+	t.Helper()
+	var protocols http.Protocols
+	protocols.SetHTTP1(true)
+	protocols.SetUnencryptedHTTP2(true)
+	l := newMemListener()
+	srv := &http.Server{Handler: h, Protocols: &protocols}
+	go srv.Serve(l)
+	t.Cleanup(func() { srv.Close() })
+
+	httpClient := func(h2 bool) *http.Client {
+		var p http.Protocols
+		if h2 {
+			p.SetUnencryptedHTTP2(true)
+		} else {
+			p.SetHTTP1(true)
+		}
+		return &http.Client{Transport: &http.Transport{DialContext: l.DialContext, Protocols: &p}}
+	}
+	const base = "http://burnenv.test"
+	return map[string]burnenvv1connect.DropServiceClient{
+		"connect":  burnenvv1connect.NewDropServiceClient(httpClient(false), base),
+		"grpc":     burnenvv1connect.NewDropServiceClient(httpClient(true), base, connect.WithGRPC()),
+		"grpc-web": burnenvv1connect.NewDropServiceClient(httpClient(false), base, connect.WithGRPCWeb()),
+	}
+}
+
+// wantRPCError checks err carries a Connect code and the stable error code.
+func wantRPCError(t *testing.T, err error, code connect.Code, stable apierr.Code) {
+	t.Helper()
+	var ce *connect.Error
+	if !errors.As(err, &ce) {
+		t.Fatalf("got %v, want a %v error", err, code)
+	}
+	if ce.Code() != code {
+		t.Errorf("code %v, want %v (%v)", ce.Code(), code, err)
+	}
+	if got := ce.Meta().Get(HeaderErrorCode); got != string(stable) {
+		t.Errorf("%s = %q, want %q", HeaderErrorCode, got, stable)
+	}
+}
+
+func TestRPCDropLifecycle(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	for name, c := range rpcClients(t, Handler(newTestStore(t), DefaultConfig(), nil, nil)) {
+		t.Run(name, func(t *testing.T) {
+			info, err := c.GetInfo(ctx, connect.NewRequest(&burnenvv1.GetInfoRequest{}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if info.Msg.GetLimits().GetMaxMaxViews() != DefaultMaxMaxViews {
+				t.Errorf("max_max_views = %d", info.Msg.GetLimits().GetMaxMaxViews())
+			}
+
+			payload := &burnenvv1.EncryptedPayload{
+				Ciphertext:  []byte("ciphertext"),
+				Salt:        []byte("salt-salt-salt-s"),
+				Iv:          []byte("iv-iv-iv-iv-"),
+				Kdf:         &burnenvv1.KDFParams{Algorithm: "argon2id", Time: 3, Memory: 65536, Threads: 4},
+				Expiry:      time.Now().Add(10 * time.Minute).Unix(),
+				MaxViews:    1,
+				ContentType: "application/x-tar",
+			}
+			created, err := c.CreateDrop(ctx, connect.NewRequest(&burnenvv1.CreateDropRequest{Payload: payload}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			id := created.Msg.GetId()
+
+			// Status never consumes a view
+			for range 2 {
+				st, err := c.GetDropStatus(ctx, connect.NewRequest(&burnenvv1.GetDropStatusRequest{Id: id}))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if st.Msg.GetViewsRemaining() != 1 || st.Msg.GetMaxViews() != 1 || st.Msg.GetExpiry() != payload.Expiry {
+					t.Errorf("status %v, want 1 of 1 views, expiry %d", st.Msg, payload.Expiry)
+				}
+			}
+
+			// Watch the drop while it is retrieved and burned
+			watch, err := c.WatchDrop(ctx, connect.NewRequest(&burnenvv1.WatchDropRequest{Id: id}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer watch.Close()
+			watch.ResponseHeader() // Waits until the server has subscribed
+
+			got, err := c.GetDrop(ctx, connect.NewRequest(&burnenvv1.GetDropRequest{Id: id}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			p := got.Msg.GetPayload()
+			if p.GetContentType() != payload.ContentType {
+				t.Errorf("content_type %q, want %q", p.GetContentType(), payload.ContentType)
+			}
+			if !bytes.Equal(p.GetCiphertext(), payload.Ciphertext) || !bytes.Equal(p.GetIv(), payload.Iv) || p.GetKdf().GetMemory() != 65536 {
+				t.Errorf("payload changed in transit: %v", p)
+			}
+
+			var kinds []burnenvv1.DropEvent_Kind
+			for watch.Receive() {
+				kinds = append(kinds, watch.Msg().GetKind())
+			}
+			if err := watch.Err(); err != nil {
+				t.Fatal(err)
+			}
+			want := []burnenvv1.DropEvent_Kind{burnenvv1.DropEvent_KIND_RETRIEVED, burnenvv1.DropEvent_KIND_BURNED}
+			if len(kinds) != 2 || kinds[0] != want[0] || kinds[1] != want[1] {
+				t.Errorf("events %v, want %v", kinds, want)
+			}
+
+			// Burned: every operation now reports NotFound
+			_, err = c.GetDrop(ctx, connect.NewRequest(&burnenvv1.GetDropRequest{Id: id}))
+			wantRPCError(t, err, connect.CodeNotFound, apierr.NotFound)
+			_, err = c.GetDropStatus(ctx, connect.NewRequest(&burnenvv1.GetDropStatusRequest{Id: id}))
+			wantRPCError(t, err, connect.CodeNotFound, apierr.NotFound)
+			_, err = c.RevokeDrop(ctx, connect.NewRequest(&burnenvv1.RevokeDropRequest{Id: id}))
+			wantRPCError(t, err, connect.CodeNotFound, apierr.NotFound)
+			stream, err := c.WatchDrop(ctx, connect.NewRequest(&burnenvv1.WatchDropRequest{Id: id}))
+			if err == nil {
+				for stream.Receive() {
+				}
+				err = stream.Err()
+				stream.Close()
+			}
+			wantRPCError(t, err, connect.CodeNotFound, apierr.NotFound)
+
+			// Revoke ends a watch
+			created, err = c.CreateDrop(ctx, connect.NewRequest(&burnenvv1.CreateDropRequest{Payload: payload}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			id = created.Msg.GetId()
+			watch, err = c.WatchDrop(ctx, connect.NewRequest(&burnenvv1.WatchDropRequest{Id: id}))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer watch.Close()
+			watch.ResponseHeader()
+			if _, err := c.RevokeDrop(ctx, connect.NewRequest(&burnenvv1.RevokeDropRequest{Id: id})); err != nil {
+				t.Fatal(err)
+			}
+			if !watch.Receive() || watch.Msg().GetKind() != burnenvv1.DropEvent_KIND_REVOKED {
+				t.Errorf("watch after revoke: %v, %v", watch.Msg(), watch.Err())
+			}
+		})
+	}
+}
+
+func TestRPCValidation(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	c := rpcClients(t, Handler(newTestStore(t), DefaultConfig(), nil, nil))["connect"]
+
+	_, err := c.CreateDrop(ctx, connect.NewRequest(&burnenvv1.CreateDropRequest{}))
+	if connect.CodeOf(err) != connect.CodeInvalidArgument {
+		t.Errorf("missing payload: %v", err)
+	}
+	_, err = c.CreateDrop(ctx, connect.NewRequest(&burnenvv1.CreateDropRequest{Payload: &burnenvv1.EncryptedPayload{
+		Ciphertext: []byte("c"), Salt: []byte("s"), Iv: []byte("i"),
+		Kdf:    &burnenvv1.KDFParams{Algorithm: "argon2id"},
+		Expiry: time.Now().Add(time.Hour).Unix(), MaxViews: DefaultMaxMaxViews + 1,
+	}}))
+	wantRPCError(t, err, connect.CodeInvalidArgument, apierr.BadRequest)
+}
+
+// TestNotFoundMetric checks JSON and RPC lookups of missing drops feed the
+// same not-found counter.
+func TestNotFoundMetric(t *testing.T) {
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	store := newTestStore(t)
+	m := NewMetrics(store)
+	h := m.Middleware(Handler(store, DefaultConfig(), nil, nil))
+
+	if rec := do(t, h, "GET", "/v1/drop/missing", nil, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("GET missing drop: %d", rec.Code)
+	}
+	for name, c := range rpcClients(t, h) {
+		_, err := c.GetDrop(ctx, connect.NewRequest(&burnenvv1.GetDropRequest{Id: "missing"}))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Errorf("%s GetDrop: %v", name, err)
+		}
+		_, err = c.GetDropStatus(ctx, connect.NewRequest(&burnenvv1.GetDropStatusRequest{Id: "missing"}))
+		if connect.CodeOf(err) != connect.CodeNotFound {
+			t.Errorf("%s GetDropStatus: %v", name, err)
+		}
+	}
+	// Revoking an unknown drop is not a lookup
+	if rec := do(t, h, "DELETE", "/v1/drop/missing", nil, nil); rec.Code != http.StatusNotFound {
+		t.Fatalf("DELETE missing drop: %d", rec.Code)
+	}
+
+	var b strings.Builder
+	m.write(&b)
+	if want := "burnenv_drops_not_found_total 7\n"; !strings.Contains(b.String(), want) {
+		t.Errorf("metrics lack %q:\n%s", want, b.String())
+	}
+	// Route labels survive the middleware's request copy
+	if want := `route="GET /v1/drop/{id}",code="404"} 1`; !strings.Contains(b.String(), want) {
+		t.Errorf("metrics lack %q:\n%s", want, b.String())
+	}
+}
@@ -0,0 +1,85 @@
+package server
+
+import (
+	"net/http"
+	"slices"
+	"strings"
+
+	"connectrpc.com/cors"
+	"github.com/yesahem/burnenv/internal/apierr"
+	"github.com/yesahem/burnenv/internal/codes"
+	"github.com/yesahem/burnenv/internal/pow"
+)
+
+// apiCSP applies to API responses: JSON is never rendered or framed.
+// Routes serving HTML (the /d/{id} page) replace it with their own policy.
+const apiCSP = "default-src 'none'; frame-ancestors 'none'"
+
+// hstsMaxAge is one year, the usual preload requirement.
+const hstsMaxAge = "max-age=31536000; includeSubDomains"
+
+// corsMaxAge is how long browsers may cache a preflight result (seconds).
+const corsMaxAge = "600"
+
+// CORS request and response headers: the REST API's own, plus those the
+// Connect, gRPC and gRPC-Web protocols need for the RPC service.
+var (
+	corsAllowHeaders = strings.Join(append([]string{
+		"Authorization", HeaderRequestID, pow.HeaderChallenge, pow.HeaderNonce, codes.HeaderToken,
+	}, cors.AllowedHeaders()...), ", ")
+	corsExposeHeaders = strings.Join(append([]string{
+		HeaderRequestID, HeaderErrorCode,
+	}, cors.ExposedHeaders()...), ", ")
+)
+
+// Security adds security headers to every response and answers CORS
+// requests from cfg.CORSOrigins. Secrets and links must never be cached by
+// proxies or leak via Referer, so no-store and no-referrer are unconditional.
+// HSTS is sent when the request arrived over TLS or the public base URL is
+// https (TLS terminated by a proxy).
+func Security(cfg Config, next http.Handler) http.Handler {
+	hsts := strings.HasPrefix(cfg.BaseURL, "https://")
+	anyOrigin := slices.Contains(cfg.CORSOrigins, "*")
+	allowed := func(origin string) bool {
+		return anyOrigin || slices.Contains(cfg.CORSOrigins, origin)
+	}
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := w.Header()
+		h.Set("Cache-Control", "no-store")
+		h.Set("Referrer-Policy", "no-referrer")
+		h.Set("X-Content-Type-Options", "nosniff")
+		h.Set("X-Frame-Options", "DENY")
+		h.Set("Content-Security-Policy", apiCSP)
+		if hsts || r.TLS != nil {
+			h.Set("Strict-Transport-Security", hstsMaxAge)
+		}
+
+		origin := r.Header.Get("Origin")
+		if origin == "" {
+			next.ServeHTTP(w, r)
+			return
+		}
+		h.Add("Vary", "Origin")
+		ok := allowed(origin)
+
+		// Preflight: answered here, never reaches the API handlers
+		if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+			if !ok {
+				writeError(w, http.StatusForbidden, apierr.Forbidden, "origin not allowed")
+				return
+			}
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE")
+			h.Set("Access-Control-Allow-Headers", corsAllowHeaders)
+			h.Set("Access-Control-Max-Age", corsMaxAge)
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+		if ok {
+			h.Set("Access-Control-Allow-Origin", origin)
+			h.Set("Access-Control-Expose-Headers", corsExposeHeaders)
+		}
+		next.ServeHTTP(w, r)
+	})
+}
@@ -0,0 +1,110 @@
+package server
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/yesahem/burnenv/gen/burnenv/v1/burnenvv1connect"
+	"github.com/yesahem/burnenv/internal/codes"
+	"github.com/yesahem/burnenv/internal/pow"
+)
+
+// TestPreflight checks that a browser may send each route family's
+// request with the headers its client sets.
+func TestPreflight(t *testing.T) {
+	const origin = "https://tools.example.com"
+	cfg := DefaultConfig()
+	cfg.CORSOrigins = []string{origin}
+	h := Security(cfg, Handler(newTestStore(t), cfg, nil, nil))
+
+	for _, tc := range []struct {
+		name    string
+		method  string
+		path    string
+		headers []string
+	}{
+		{"info", "GET", "/v1/info", nil},
+		{"challenge", "GET", "/v1/challenge", nil},
+		{"create drop", "POST", "/v1/drop", []string{"Content-Type", "Authorization", pow.HeaderChallenge, pow.HeaderNonce, HeaderRequestID}},
+		{"get drop", "GET", "/v1/drop/abc", nil},
+		{"revoke drop", "DELETE", "/v1/drop/abc", nil},
+		{"open code", "POST", "/v1/code", []string{"Content-Type", "Authorization", pow.HeaderChallenge, pow.HeaderNonce}},
+		{"code status", "GET", "/v1/code/1", []string{codes.HeaderToken}},
+		{"close code", "DELETE", "/v1/code/1", []string{"Content-Type", codes.HeaderToken}},
+		{"claim code", "POST", "/v1/code/1/claim", []string{"Content-Type", "Authorization", pow.HeaderChallenge, pow.HeaderNonce}},
+		{"seal code", "PUT", "/v1/code/1/claim/0", []string{"Content-Type", "Authorization", codes.HeaderToken, pow.HeaderChallenge, pow.HeaderNonce}},
+		{"connect", "POST", burnenvv1connect.DropServiceCreateDropProcedure, []string{"Content-Type", "Connect-Protocol-Version", "Connect-Timeout-Ms", "Authorization", pow.HeaderChallenge, pow.HeaderNonce}},
+		{"connect GET", "GET", burnenvv1connect.DropServiceGetInfoProcedure, []string{"Connect-Protocol-Version"}},
+		{"grpc-web", "POST", burnenvv1connect.DropServiceGetDropProcedure, []string{"Content-Type", "X-Grpc-Web", "X-User-Agent", "Grpc-Timeout"}},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			req := httptest.NewRequest("OPTIONS", tc.path, nil)
+			req.Header.Set("Origin", origin)
+			req.Header.Set("Access-Control-Request-Method", tc.method)
+			if len(tc.headers) > 0 {
+				req.Header.Set("Access-Control-Request-Headers", strings.ToLower(strings.Join(tc.headers, ",")))
+			}
+			rec := httptest.NewRecorder()
+			h.ServeHTTP(rec, req)
+			if rec.Code != http.StatusNoContent {
+				t.Fatalf("status %d, want 204: %s", rec.Code, rec.Body)
+			}
+			if got := rec.Header().Get("Access-Control-Allow-Origin"); got != origin {
+				t.Errorf("Allow-Origin %q, want %q", got, origin)
+			}
+			if methods := strings.Split(rec.Header().Get("Access-Control-Allow-Methods"), ", "); !slices.Contains(methods, tc.method) {
+				t.Errorf("method %s not in Allow-Methods %v", tc.method, methods)
+			}
+			allowed := headerList(rec.Header().Get("Access-Control-Allow-Headers"))
+			for _, name := range tc.headers {
+				if !slices.Contains(allowed, http.CanonicalHeaderKey(name)) {
+					t.Errorf("header %s not in Allow-Headers %v", name, allowed)
+				}
+			}
+		})
+	}
+
+	// Other origins are refused
+	req := httptest.NewRequest("OPTIONS", "/v1/drop", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	if rec.Code != http.StatusForbidden || rec.Header().Get("Access-Control-Allow-Origin") != "" {
+		t.Errorf("foreign origin: status %d, Allow-Origin %q", rec.Code, rec.Header().Get("Access-Control-Allow-Origin"))
+	}
+}
+
+// TestCORSExposeHeaders checks browsers can read the request ID and the
+// RPC error metadata.
+func TestCORSExposeHeaders(t *testing.T) {
+	const origin = "https://tools.example.com"
+	cfg := DefaultConfig()
+	cfg.CORSOrigins = []string{"*"}
+	h := Security(cfg, Handler(newTestStore(t), cfg, nil, nil))
+
+	req := httptest.NewRequest("GET", "/v1/info", nil)
+	req.Header.Set("Origin", origin)
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, req)
+	exposed := headerList(rec.Header().Get("Access-Control-Expose-Headers"))
+	for _, name := range []string{HeaderRequestID, HeaderErrorCode, "Grpc-Status", "Grpc-Message"} {
+		if !slices.Contains(exposed, http.CanonicalHeaderKey(name)) {
+			t.Errorf("%s not in Expose-Headers %v", name, exposed)
+		}
+	}
+}
+
+// headerList splits a comma-separated header value into canonical names.
+func headerList(v string) []string {
+	var out []string
+	for _, s := range strings.Split(v, ",") {
+		if s = strings.TrimSpace(s); s != "" {
+			out = append(out, http.CanonicalHeaderKey(s))
+		}
+	}
+	return out
+}
@@ -4,6 +4,7 @@ package server
 
 import (
 	"sync"
+	"sync/atomic"
 	"time"
 )
 
@@ -11,11 +12,48 @@ import (
 type NotFoundReason int
 
 const (
-	ReasonNotFound NotFoundReason = iota // Never existed or already deleted
-	ReasonExpired                        // TTL expired
-	ReasonMaxViews                       // Max views reached (burned)
+	ReasonNotFound    NotFoundReason = iota // Never existed or already deleted
+	ReasonExpired                           // TTL expired
+	ReasonMaxViews                          // Max views reached (burned)
+	ReasonMaxAttempts                       // Code guessed too many times (burned)
 )
 
+// EventKind is a drop lifecycle transition.
+type EventKind int
+
+const (
+	EventCreated   EventKind = iota // Put
+	EventRetrieved                  // Successful GET (one view consumed)
+	EventBurned                     // Last view consumed; blob deleted
+	EventExpired                    // TTL passed before all views were used
+	EventRevoked                    // Manual DELETE
+)
+
+// String returns the lowercase event name used in metrics and logs.
+func (k EventKind) String() string {
+	switch k {
+	case EventCreated:
+		return "created"
+	case EventRetrieved:
+		return "retrieved"
+	case EventBurned:
+		return "burned"
+	case EventExpired:
+		return "expired"
+	case EventRevoked:
+		return "revoked"
+	}
+	return "unknown"
+}
+
+// Event describes one lifecycle transition of a stored drop.
+type Event struct {
+	Kind EventKind
+	ID   string
+	Key  string // Name of the API key that created the drop ("" if none)
+	Time time.Time
+}
+
 // storedSecret holds an encrypted payload with view-tracking.
 // Server never parses ciphertext; it stores the raw blob.
 type storedSecret struct {
@@ -23,6 +61,7 @@ type storedSecret struct {
 	ViewsRemaining int
 	Expiry         time.Time
 	MaxViews       int
+	Key            string // Creating API key name, for audit events
 }
 
 // Store is an in-memory store with TTL and max-views enforcement.
@@ -30,35 +69,73 @@ type storedSecret struct {
 type Store struct {
 	mu      sync.RWMutex
 	secrets map[string]*storedSecret
+	live    atomic.Int64 // len(secrets), readable without the lock
+	// Short-code rendezvous channels (burnenv send/receive)
+	nameplates map[int]*nameplate
 	// Background cleanup of expired entries
 	stopCleanup chan struct{}
+	cleanupDone chan struct{}
+	stopOnce    sync.Once
+	// Lifecycle observers (metrics, audit); called with mu held
+	observers []func(Event)
 }
 
 // NewStore creates an in-memory store and starts TTL cleanup.
 func NewStore() *Store {
 	s := &Store{
 		secrets:     make(map[string]*storedSecret),
+		nameplates:  make(map[int]*nameplate),
 		stopCleanup: make(chan struct{}),
+		cleanupDone: make(chan struct{}),
 	}
 	go s.cleanupLoop()
 	return s
 }
 
-// Stop halts the cleanup goroutine (for graceful shutdown).
+// Stop halts the cleanup goroutine and waits for it to exit (for graceful
+// shutdown). Safe to call more than once. The store is in-memory only, so
+// there is no state to flush: stopping it discards every secret.
 func (s *Store) Stop() {
-	close(s.stopCleanup)
+	s.stopOnce.Do(func() {
+		close(s.stopCleanup)
+	})
+	<-s.cleanupDone
+}
+
+// AddObserver registers fn to be called for every lifecycle event.
+// fn runs while the store lock is held, so it must be fast and must not
+// call back into the store. Register observers before serving requests.
+func (s *Store) AddObserver(fn func(Event)) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.observers = append(s.observers, fn)
+}
+
+// emit notifies observers. Caller must hold s.mu.
+func (s *Store) emit(kind EventKind, id, key string) {
+	if len(s.observers) == 0 {
+		return
+	}
+	ev := Event{Kind: kind, ID: id, Key: key, Time: time.Now()}
+	for _, fn := range s.observers {
+		fn(ev)
+	}
 }
 
-// Put stores an encrypted blob. Returns id.
-func (s *Store) Put(id string, blob []byte, maxViews int, expiry time.Time) {
+// Put stores an encrypted blob. key names the API key that created it
+// (empty when API keys are disabled) and is only used in lifecycle events.
+func (s *Store) Put(id string, blob []byte, maxViews int, expiry time.Time, key string) {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 	s.secrets[id] = &storedSecret{
-		Blob:          blob,
+		Blob:           blob,
 		ViewsRemaining: maxViews,
-		Expiry:        expiry,
-		MaxViews:      maxViews,
+		Expiry:         expiry,
+		MaxViews:       maxViews,
+		Key:            key,
 	}
+	s.live.Store(int64(len(s.secrets)))
+	s.emit(EventCreated, id, key)
 }
 
 // Get retrieves the blob and decrements views. Deletes when views hit 0.
@@ -78,31 +155,73 @@ func (s *Store) GetWithReason(id string) ([]byte, NotFoundReason) {
 		return nil, ReasonNotFound
 	}
 	if time.Now().After(sec.Expiry) {
-		delete(s.secrets, id)
+		s.removeLocked(id)
+		s.emit(EventExpired, id, sec.Key)
 		return nil, ReasonExpired
 	}
 	if sec.ViewsRemaining <= 0 {
-		delete(s.secrets, id)
+		s.removeLocked(id)
 		return nil, ReasonMaxViews
 	}
 	blob := sec.Blob
 	sec.ViewsRemaining--
+	s.emit(EventRetrieved, id, sec.Key)
 	if sec.ViewsRemaining <= 0 {
-		delete(s.secrets, id)
+		s.removeLocked(id)
+		s.emit(EventBurned, id, sec.Key)
 	}
 	return blob, ReasonNotFound // Success indicated by non-nil blob
 }
 
+// Status returns a drop's remaining and maximum views and its expiry,
+// without consuming a view. ok is false if id is unknown or expired.
+func (s *Store) Status(id string) (views, maxViews int, expiry time.Time, ok bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	sec, found := s.secrets[id]
+	if !found || !time.Now().Before(sec.Expiry) {
+		return 0, 0, time.Time{}, false
+	}
+	return sec.ViewsRemaining, sec.MaxViews, sec.Expiry, true
+}
+
 // Delete removes a secret (manual revoke).
 func (s *Store) Delete(id string) bool {
 	s.mu.Lock()
 	defer s.mu.Unlock()
-	_, ok := s.secrets[id]
-	delete(s.secrets, id)
+	sec, ok := s.secrets[id]
+	s.removeLocked(id)
+	if ok {
+		s.emit(EventRevoked, id, sec.Key)
+	}
 	return ok
 }
 
+// removeLocked deletes a secret. Caller must hold s.mu.
+func (s *Store) removeLocked(id string) {
+	delete(s.secrets, id)
+	s.live.Store(int64(len(s.secrets)))
+}
+
+// Len returns the number of stored secrets without taking the lock, so
+// hot unauthenticated paths can read it cheaply. Expired secrets count
+// until they are touched or cleaned up.
+func (s *Store) Len() int {
+	return int(s.live.Load())
+}
+
+// Stats returns the number of stored secrets and their total blob size.
+func (s *Store) Stats() (entries int, bytes int64) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, sec := range s.secrets {
+		bytes += int64(len(sec.Blob))
+	}
+	return len(s.secrets), bytes
+}
+
 func (s *Store) cleanupLoop() {
+	defer close(s.cleanupDone)
 	ticker := time.NewTicker(30 * time.Second)
 	defer ticker.Stop()
 	for {
@@ -121,7 +240,13 @@ func (s *Store) expireOld() {
 	now := time.Now()
 	for id, sec := range s.secrets {
 		if now.After(sec.Expiry) {
-			delete(s.secrets, id)
+			s.removeLocked(id)
+			s.emit(EventExpired, id, sec.Key)
+		}
+	}
+	for ch, np := range s.nameplates {
+		if now.After(np.Expiry) {
+			delete(s.nameplates, ch)
 		}
 	}
 }
@@ -3,29 +3,42 @@ package server
 import (
 	"fmt"
 	"net/http"
+	"slices"
 	"time"
 )
 
 // Server-side size and input validation limits.
+// The Default* values are overridable per deployment via Config.
 const (
 	// Size limits
-	MaxRequestBodyBytes = 2 * 1024 * 1024 // 2 MB total request body
-	MaxCiphertextLen    = 2 * 1024 * 1024 // 1.5 MB base64 ciphertext string (~1.5 MB decoded)
-	MaxSaltLen          = 64              // base64-encoded salt
-	MaxIVLen            = 64              // base64-encoded IV
+	DefaultMaxRequestBodyBytes = 2 * 1024 * 1024 // 2 MB total request body
+	DefaultMaxCiphertextLen    = 2 * 1024 * 1024 // 1.5 MB base64 ciphertext string (~1.5 MB decoded)
+	MaxSaltLen                 = 64              // base64-encoded salt
+	MaxIVLen                   = 64              // base64-encoded IV
+	MaxContentTypeLen          = 128             // Optional MIME type of the plaintext
 
-	// Expiry limits (server-side; client TUI uses 2-10 min)
-	MinExpirySeconds = 60           // 1 minute minimum
-	MaxExpirySeconds = 24 * 60 * 60 // 24 hours maximum
+	// Expiry limits
+	DefaultMinExpirySeconds = 60           // 1 minute minimum
+	DefaultMaxExpirySeconds = 24 * 60 * 60 // 24 hours maximum
 
-	// Max views limits (server-side; client TUI uses 1-5)
-	MinMaxViews = 1
-	MaxMaxViews = 100
+	// Max views limits
+	DefaultMinMaxViews = 1
+	DefaultMaxMaxViews = 100
+
+	// Short-code limits (burnenv send/receive)
+	MaxPakeLen      = 64   // base64-encoded SPAKE2 message
+	MaxProofLen     = 64   // base64-encoded delivery confirmation
+	MaxCodeAttempts = 3    // Receiver claims per code before it burns
+	MaxOpenCodes    = 1000 // Channels open at once; keeps codes short
 )
 
+// AcceptedKDFAlgorithms lists the key-derivation algorithms clients may use.
+// The server cannot check the key, but rejects payloads no client can open.
+var AcceptedKDFAlgorithms = []string{"argon2id"}
+
 // validateRequest performs full server-side validation of the create request.
 // Returns an HTTP status code and error message if validation fails.
-func validateRequest(req *dropCreateRequest) (int, string) {
+func validateRequest(req *dropCreateRequest, cfg *Config) (int, string) {
 	// --- Required fields ---
 	if req.Ciphertext == "" {
 		return http.StatusBadRequest, "missing required field: ciphertext"
@@ -37,10 +50,15 @@ func validateRequest(req *dropCreateRequest) (int, string) {
 		return http.StatusBadRequest, "missing required field: iv"
 	}
 
+	if !slices.Contains(AcceptedKDFAlgorithms, req.KDF.Algorithm) {
+		return http.StatusBadRequest,
+			fmt.Sprintf("unsupported kdf algorithm %q", req.KDF.Algorithm)
+	}
+
 	// --- Size limits ---
-	if len(req.Ciphertext) > MaxCiphertextLen {
+	if len(req.Ciphertext) > cfg.MaxCiphertextLen {
 		return http.StatusRequestEntityTooLarge,
-			fmt.Sprintf("ciphertext exceeds maximum size (limit: %.1f MB)", float64(MaxCiphertextLen)/(1024*1024))
+			fmt.Sprintf("ciphertext exceeds maximum size (limit: %.1f MB)", float64(cfg.MaxCiphertextLen)/(1024*1024))
 	}
 	if len(req.Salt) > MaxSaltLen {
 		return http.StatusBadRequest,
@@ -50,31 +68,56 @@ func validateRequest(req *dropCreateRequest) (int, string) {
 		return http.StatusBadRequest,
 			fmt.Sprintf("iv exceeds maximum length (%d bytes)", MaxIVLen)
 	}
+	if len(req.ContentType) > MaxContentTypeLen {
+		return http.StatusBadRequest,
+			fmt.Sprintf("content_type exceeds maximum length (%d bytes)", MaxContentTypeLen)
+	}
 
 	// --- Expiry validation ---
-	now := time.Now().Unix()
-	if req.Expiry <= now {
-		return http.StatusBadRequest, "expiry must be in the future"
+	if status, msg := validateExpiry(req.Expiry, cfg); status != 0 {
+		return status, msg
 	}
-	duration := req.Expiry - now
-	if duration < MinExpirySeconds {
+
+	// --- Max views validation ---
+	if req.MaxViews < cfg.MinMaxViews {
 		return http.StatusBadRequest,
-			fmt.Sprintf("expiry must be at least %d seconds from now", MinExpirySeconds)
+			fmt.Sprintf("max_views must be at least %d", cfg.MinMaxViews)
 	}
-	if duration > MaxExpirySeconds {
+	if req.MaxViews > cfg.MaxMaxViews {
 		return http.StatusBadRequest,
-			fmt.Sprintf("expiry cannot exceed %d hours from now", MaxExpirySeconds/3600)
+			fmt.Sprintf("max_views cannot exceed %d", cfg.MaxMaxViews)
 	}
 
-	// --- Max views validation ---
-	if req.MaxViews < MinMaxViews {
+	return 0, ""
+}
+
+// validateExpiry checks an absolute unix expiry against the server limits.
+func validateExpiry(expiry int64, cfg *Config) (int, string) {
+	now := time.Now().Unix()
+	if expiry <= now {
+		return http.StatusBadRequest, "expiry must be in the future"
+	}
+	// Compared in seconds: a far-off expiry overflows a Duration
+	secs := expiry - now
+	if secs > int64(cfg.MaxExpiry/time.Second) {
 		return http.StatusBadRequest,
-			fmt.Sprintf("max_views must be at least %d", MinMaxViews)
+			fmt.Sprintf("expiry cannot exceed %s from now", cfg.MaxExpiry)
 	}
-	if req.MaxViews > MaxMaxViews {
+	if time.Duration(secs)*time.Second < cfg.MinExpiry {
 		return http.StatusBadRequest,
-			fmt.Sprintf("max_views cannot exceed %d", MaxMaxViews)
+			fmt.Sprintf("expiry must be at least %s from now", cfg.MinExpiry)
 	}
+	return 0, ""
+}
 
+// validatePake checks a base64 SPAKE2 message is present and bounded.
+func validatePake(pake string) (int, string) {
+	if pake == "" {
+		return http.StatusBadRequest, "missing required field: pake"
+	}
+	if len(pake) > MaxPakeLen {
+		return http.StatusBadRequest,
+			fmt.Sprintf("pake exceeds maximum length (%d bytes)", MaxPakeLen)
+	}
 	return 0, ""
 }
@@ -0,0 +1,54 @@
+package server
+
+import (
+	"net/http"
+	"testing"
+	"time"
+)
+
+func TestValidateExpiry(t *testing.T) {
+	cfg := DefaultConfig()
+	now := time.Now().Unix()
+	for _, tc := range []struct {
+		name   string
+		expiry int64
+		ok     bool
+	}{
+		{"past", now - 60, false},
+		{"now", now, false},
+		{"below min", now + 1, false},
+		{"min", now + int64(cfg.MinExpiry/time.Second) + 1, true},
+		{"max", now + int64(cfg.MaxExpiry/time.Second) - 1, true},
+		{"above max", now + int64(cfg.MaxExpiry/time.Second) + 60, false},
+		// Wraps to about an hour as a time.Duration
+		{"overflow", now + 1<<55 + 3600, false},
+		{"max int64", 1<<63 - 1, false},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			status, msg := validateExpiry(tc.expiry, &cfg)
+			if tc.ok && status != 0 {
+				t.Errorf("refused: %d %s", status, msg)
+			}
+			if !tc.ok && status != http.StatusBadRequest {
+				t.Errorf("status %d, want 400", status)
+			}
+		})
+	}
+}
+
+// TestFarExpiryRefused checks the overflow can't reach the store through
+// the create endpoints.
+func TestFarExpiryRefused(t *testing.T) {
+	h := Handler(newTestStore(t), DefaultConfig(), nil, nil)
+	far := time.Now().Unix() + 1<<55 + 3600
+
+	drop := testDrop("Y2lwaGVydGV4dA==")
+	drop["expiry"] = far
+	if rec := do(t, h, "POST", "/v1/drop", drop, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /v1/drop: status %d, want 400: %s", rec.Code, rec.Body)
+	}
+	code := map[string]any{"pake": "cGFrZQ==", "expiry": far}
+	if rec := do(t, h, "POST", "/v1/code", code, nil); rec.Code != http.StatusBadRequest {
+		t.Errorf("POST /v1/code: status %d, want 400: %s", rec.Code, rec.Body)
+	}
+}
@@ -23,6 +23,10 @@ var (
 		Foreground(lipgloss.Color("39")).
 		Bold(true)
 
+	// Warning for problems that don't stop the flow
+	Warning = lipgloss.NewStyle().
+		Foreground(lipgloss.Color("214"))
+
 	// Muted for secondary info
 	Muted = lipgloss.NewStyle().
 		Foreground(lipgloss.Color("241"))
@@ -11,19 +11,24 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yesahem/burnenv/internal/client"
 	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/expiry"
+	"github.com/yesahem/burnenv/internal/passphrase"
 	"github.com/yesahem/burnenv/internal/store"
 )
 
 type createResult struct {
-	link string
-	err  error
+	link      string
+	expiresAt time.Time
+	err       error
 }
 
 type createModel struct {
 	secretInput   textinput.Model
 	passwordInput textinput.Model
-	expiry        int
+	exp           expiry.Spec
 	maxViews      int
+	minStrength   int
+	pwErr         error
 	serverURL     string
 	focused       int
 	secret        string
@@ -34,7 +39,7 @@ type createModel struct {
 	height        int
 }
 
-func newCreateModel(expiry, maxViews int, serverURL string) createModel {
+func newCreateModel(exp expiry.Spec, maxViews, minStrength int, serverURL string) createModel {
 	si := textinput.New()
 	si.Placeholder = "Paste or type your secret..."
 	si.Width = 72
@@ -47,8 +52,9 @@ func newCreateModel(expiry, maxViews int, serverURL string) createModel {
 	return createModel{
 		secretInput:   si,
 		passwordInput: pi,
-		expiry:        expiry,
+		exp:           exp,
 		maxViews:      maxViews,
+		minStrength:   minStrength,
 		serverURL:     serverURL,
 		focused:       0,
 		width:         80,
@@ -93,6 +99,11 @@ func (m createModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, textinput.Blink
 			}
 			if m.focused == 1 {
+				if s := passphrase.Estimate([]byte(m.passwordInput.Value())); s.Score < m.minStrength {
+					m.pwErr = fmt.Errorf("password is %s; at least %s is required", s.Label(), passphrase.Labels[m.minStrength])
+					return m, nil
+				}
+				m.pwErr = nil
 				m.password = m.passwordInput.Value()
 				m.focused = 2
 				m.passwordInput.Blur()
@@ -127,8 +138,8 @@ func (m createModel) doCreateSecret() tea.Msg {
 		return createResult{err: err}
 	}
 
-	expiry := time.Now().Add(time.Duration(m.expiry) * time.Minute).Unix()
-	payload.Expiry = expiry
+	expiresAt := m.exp.At(time.Now())
+	payload.Expiry = expiresAt.Unix()
 	payload.MaxViews = m.maxViews
 
 	url := m.serverURL
@@ -149,7 +160,7 @@ func (m createModel) doCreateSecret() tea.Msg {
 	if err != nil {
 		return createResult{err: err}
 	}
-	return createResult{link: link}
+	return createResult{link: link, expiresAt: expiresAt}
 }
 
 func (m createModel) View() string {
@@ -170,7 +181,7 @@ func (m createModel) View() string {
 			b.WriteString(Success.Render("✓ Secret encrypted.\n\n"))
 			b.WriteString("Burn link:\n")
 			b.WriteString(Link.Render(m.result.link) + "\n\n")
-			b.WriteString(Muted.Render(fmt.Sprintf("Expires: %d min | Max views: %d", m.expiry, m.maxViews)))
+			b.WriteString(Muted.Render(fmt.Sprintf("Expires %s | Max views: %d", expiry.Describe(m.result.expiresAt, time.Now()), m.maxViews)))
 			content = Box.Width(boxWidth).Render(b.String())
 		}
 	} else {
@@ -184,7 +195,18 @@ func (m createModel) View() string {
 		} else {
 			b.WriteString(Prompt.Render("  Secret: [entered]") + "\n\n")
 			b.WriteString(Focused.Render("> Password:") + "\n")
-			b.WriteString(m.passwordInput.View() + "\n\n")
+			b.WriteString(m.passwordInput.View() + "\n")
+			if m.passwordInput.Value() != "" {
+				strength := passphrase.Estimate([]byte(m.passwordInput.Value()))
+				b.WriteString(StrengthMeter(strength) + "\n")
+				if strength.Warning != "" {
+					b.WriteString(Warning.Render("⚠ "+strength.Warning) + "\n")
+				}
+			}
+			if m.pwErr != nil {
+				b.WriteString(Error.Render("Error: "+m.pwErr.Error()) + "\n")
+			}
+			b.WriteString("\n")
 		}
 
 		b.WriteString(Muted.Render("Tab/Enter to continue • Ctrl+C to cancel"))
@@ -196,8 +218,9 @@ func (m createModel) View() string {
 }
 
 // RunCreateTUI launches the Bubble Tea TUI for create.
-func RunCreateTUI(expiry, maxViews int, serverURL string) (string, error) {
-	m := newCreateModel(expiry, maxViews, serverURL)
+// Passwords scoring below minStrength (0-4) are refused.
+func RunCreateTUI(exp expiry.Spec, maxViews, minStrength int, serverURL string) (string, error) {
+	m := newCreateModel(exp, maxViews, minStrength, serverURL)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	final, err := p.Run()
 	if err != nil {
@@ -26,15 +26,16 @@ const (
 )
 
 type mainModel struct {
-	width      int
-	height     int
+	width       int
+	height      int
 	currentView view
-	list       list.Model
-	retrieve   *retrieveModel
-	secure     *secureModel
+	list        list.Model
+	retrieve    *retrieveModel
+	secure      *secureModel
+	minStrength int // For the secure flow's password step
 }
 
-func newMainModel() mainModel {
+func newMainModel(minStrength int) mainModel {
 	items := []list.Item{
 		menuItem{title: "1. Retrieve env", desc: "Paste secure key, enter password, copy or export .env"},
 		menuItem{title: "2. Secure env", desc: "Paste secrets, lock with password, get secure key"},
@@ -56,6 +57,7 @@ func newMainModel() mainModel {
 		height:      30,
 		currentView: viewMenu,
 		list:        l,
+		minStrength: minStrength,
 	}
 }
 
@@ -68,7 +70,7 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	case tea.WindowSizeMsg:
 		m.width = msg.Width
 		m.height = msg.Height
-		
+
 		// Calculate list size based on terminal size
 		listWidth := msg.Width - 16
 		if listWidth < 50 {
@@ -82,7 +84,7 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			listHeight = 8
 		}
 		m.list.SetSize(listWidth, listHeight)
-		
+
 		if m.retrieve != nil {
 			newRetrieve, _ := m.retrieve.Update(msg)
 			if r, ok := newRetrieve.(*retrieveModel); ok {
@@ -134,7 +136,7 @@ func (m mainModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 			if strings.Contains(i.title, "2") {
 				m.currentView = viewSecure
-				s := newSecureModel(m.width, m.height)
+				s := newSecureModel(m.width, m.height, m.minStrength)
 				m.secure = &s
 				return m, m.secure.Init()
 			}
@@ -195,8 +197,9 @@ func (m mainModel) View() string {
 }
 
 // RunMainTUI launches the main BurnEnv TUI (menu + retrieve + secure flows).
-func RunMainTUI() error {
-	m := newMainModel()
+// The secure flow refuses passwords scoring below minStrength (0-4).
+func RunMainTUI(minStrength int) error {
+	m := newMainModel(minStrength)
 	p := tea.NewProgram(m, tea.WithAltScreen())
 	_, err := p.Run()
 	return err
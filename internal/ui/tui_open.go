@@ -9,6 +9,7 @@ import (
 	"github.com/charmbracelet/bubbles/textinput"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yesahem/burnenv/internal/crypto"
+	"golang.org/x/term"
 )
 
 type openResult struct {
@@ -143,9 +144,11 @@ func RunOpenTUI(payload *crypto.EncryptedPayload) ([]byte, error) {
 }
 
 // PrintPlaintextToStdout ensures plaintext goes to stdout for piping.
+// Piped output is byte for byte; a terminal gets a final newline so the
+// shell prompt starts on its own line.
 func PrintPlaintextToStdout(plaintext []byte) {
 	os.Stdout.Write(plaintext)
-	if len(plaintext) > 0 && plaintext[len(plaintext)-1] != '\n' {
+	if len(plaintext) > 0 && plaintext[len(plaintext)-1] != '\n' && term.IsTerminal(int(os.Stdout.Fd())) {
 		os.Stdout.Write([]byte{'\n'})
 	}
 }
@@ -0,0 +1,101 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/yesahem/burnenv/internal/dotenv"
+)
+
+// pickerRows is how many keys the picker shows at once.
+const pickerRows = 10
+
+// keyPicker lists the keys of a loaded .env file with checkboxes. Values
+// are never rendered, not even their length.
+type keyPicker struct {
+	source   string
+	entries  []dotenv.Entry
+	selected []bool
+	cursor   int
+}
+
+// newKeyPicker starts with every key selected.
+func newKeyPicker(source string, entries []dotenv.Entry) keyPicker {
+	entries = dotenv.Unique(entries)
+	selected := make([]bool, len(entries))
+	for i := range selected {
+		selected[i] = true
+	}
+	return keyPicker{source: source, entries: entries, selected: selected}
+}
+
+// update handles navigation and toggling keys.
+func (p *keyPicker) update(keyStr string) {
+	switch keyStr {
+	case "up", "k":
+		if p.cursor > 0 {
+			p.cursor--
+		}
+	case "down", "j":
+		if p.cursor < len(p.entries)-1 {
+			p.cursor++
+		}
+	case " ", "space", "x":
+		p.selected[p.cursor] = !p.selected[p.cursor]
+	case "a":
+		// Select all, or none when all are already selected
+		all := p.count() == len(p.entries)
+		for i := range p.selected {
+			p.selected[i] = !all
+		}
+	}
+}
+
+func (p keyPicker) count() int {
+	n := 0
+	for _, s := range p.selected {
+		if s {
+			n++
+		}
+	}
+	return n
+}
+
+// chosen returns the selected entries in file order.
+func (p keyPicker) chosen() []dotenv.Entry {
+	var out []dotenv.Entry
+	for i, e := range p.entries {
+		if p.selected[i] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+func (p keyPicker) view() string {
+	// Scroll so the cursor stays in the visible window
+	start := max(0, min(p.cursor-pickerRows/2, len(p.entries)-pickerRows))
+	end := min(start+pickerRows, len(p.entries))
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString(Muted.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n")
+	}
+	for i := start; i < end; i++ {
+		box := "[ ]"
+		if p.selected[i] {
+			box = "[x]"
+		}
+		row := fmt.Sprintf("%s %s = ••••••••", box, p.entries[i].Key)
+		if i == p.cursor {
+			b.WriteString(Focused.Render("> "+row) + "\n")
+		} else {
+			b.WriteString(Prompt.Render("  "+row) + "\n")
+		}
+	}
+	if end < len(p.entries) {
+		b.WriteString(Muted.Render(fmt.Sprintf("  ↓ %d more", len(p.entries)-end)) + "\n")
+	}
+	b.WriteString("\n" + Success.Render(fmt.Sprintf("%d of %d keys selected", p.count(), len(p.entries))))
+	return b.String()
+}
@@ -1,7 +1,9 @@
 package ui
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
 	"os"
 	"strings"
 
@@ -12,6 +14,7 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yesahem/burnenv/internal/client"
 	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/dotenv"
 	"github.com/yesahem/burnenv/internal/store"
 )
 
@@ -20,8 +23,9 @@ type retrieveStep int
 const (
 	stepKey retrieveStep = iota
 	stepPassword
-	stepResult   // show content + copy/export
-	stepExport   // waiting for path input
+	stepResult // show content + copy/export
+	stepExport // waiting for path input
+	stepMerge  // export path exists: review key diff
 	stepBurned
 )
 
@@ -33,9 +37,12 @@ type retrieveModel struct {
 	passwordInput textinput.Model
 	pathInput     textinput.Model
 	payload       *crypto.EncryptedPayload
-	plaintext []byte
-	err       error
-	done      bool
+	plaintext     []byte
+	err           error
+	done          bool
+	merge         *mergePlan
+	exportErr     error  // shown inline; the user can pick another path
+	notice        string // what the export did, shown with the burn notice
 }
 
 func newRetrieveModel(width, height int) retrieveModel {
@@ -105,7 +112,81 @@ func (m retrieveModel) doDecrypt() tea.Msg {
 }
 
 type copyDone struct{}
-type exportDone struct{ err error }
+type exportDone struct {
+	notice string
+	err    error
+}
+
+// mergePlan is an export onto an existing .env, awaiting confirmation.
+type mergePlan struct {
+	path     string
+	data     []byte
+	incoming []dotenv.Entry
+	changes  []dotenv.Change
+	err      error
+}
+
+func (p *mergePlan) count(kind dotenv.ChangeKind) int {
+	n := 0
+	for _, c := range p.changes {
+		if c.Kind == kind {
+			n++
+		}
+	}
+	return n
+}
+
+// doExport writes a new file directly; an existing one is never
+// clobbered but parsed and diffed for review.
+func (m retrieveModel) doExport(path string) tea.Cmd {
+	return func() tea.Msg {
+		data, err := os.ReadFile(path)
+		if errors.Is(err, fs.ErrNotExist) {
+			if _, err := dotenv.WriteFile(path, m.plaintext); err != nil {
+				return exportDone{err: err}
+			}
+			return exportDone{notice: "Exported to " + path}
+		}
+		if err != nil {
+			return &mergePlan{err: err}
+		}
+		existing, err := dotenv.Parse(data)
+		if err != nil {
+			return &mergePlan{err: fmt.Errorf("%s exists but can't be merged: %w", path, err)}
+		}
+		incoming, err := dotenv.Parse(m.plaintext)
+		if err != nil {
+			return &mergePlan{err: fmt.Errorf("%s exists and the secret is not a .env (%w); choose another path", path, err)}
+		}
+		return &mergePlan{path: path, data: data, incoming: incoming, changes: dotenv.Diff(existing, incoming)}
+	}
+}
+
+// doMerge writes the reviewed merge atomically, keeping a backup.
+func (m retrieveModel) doMerge(replace bool) tea.Cmd {
+	p := m.merge
+	return func() tea.Msg {
+		added, changed := p.count(dotenv.Added), p.count(dotenv.Changed)
+		if added == 0 && (changed == 0 || !replace) {
+			return exportDone{notice: p.path + " already up to date; nothing written"}
+		}
+		merged, err := dotenv.Merge(p.data, p.incoming, replace)
+		if err != nil {
+			return exportDone{err: err}
+		}
+		backup, err := dotenv.WriteFile(p.path, merged)
+		if err != nil {
+			return exportDone{err: err}
+		}
+		notice := fmt.Sprintf("Merged into %s: %d added", p.path, added)
+		if replace {
+			notice += fmt.Sprintf(", %d replaced", changed)
+		} else if changed > 0 {
+			notice += fmt.Sprintf(", %d kept", changed)
+		}
+		return exportDone{notice: notice + " (backup: " + backup + ")"}
+	}
+}
 
 func (m retrieveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch msg := msg.(type) {
@@ -131,9 +212,16 @@ func (m retrieveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "esc":
 			if m.step == stepExport {
 				m.pathInput.Blur()
+				m.exportErr = nil
 				m.step = stepResult
 				return &m, nil
 			}
+			if m.step == stepMerge {
+				m.merge = nil
+				m.pathInput.Focus()
+				m.step = stepExport
+				return &m, textinput.Blink
+			}
 			m.done = true
 			return &m, nil
 		}
@@ -189,14 +277,25 @@ func (m retrieveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if isEnter || isSpace {
 				path := strings.TrimSpace(m.pathInput.Value())
 				if path != "" {
-					return &m, func() tea.Msg {
-						err := os.WriteFile(path, m.plaintext, 0600)
-						return exportDone{err: err}
-					}
+					return &m, m.doExport(path)
 				}
 			}
 		}
 
+		// Handle stepMerge - r/k resolve conflicts, Enter merges when there are none
+		if m.step == stepMerge {
+			conflicts := m.merge.count(dotenv.Changed) > 0
+			switch {
+			case conflicts && (keyStr == "r" || keyStr == "R"):
+				return &m, m.doMerge(true)
+			case conflicts && (keyStr == "k" || keyStr == "K"):
+				return &m, m.doMerge(false)
+			case !conflicts && (isEnter || isSpace):
+				return &m, m.doMerge(false)
+			}
+			return &m, nil
+		}
+
 		// Handle stepBurned - Enter or Space to go back
 		if m.step == stepBurned {
 			if isEnter || isSpace {
@@ -230,12 +329,26 @@ func (m retrieveModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.step = stepBurned
 		return &m, nil
 
-	case exportDone:
+	case *mergePlan:
 		if msg.err != nil {
-			m.err = msg.err
-			m.step = stepResult
+			m.exportErr = msg.err
 			return &m, nil
 		}
+		m.exportErr = nil
+		m.merge = msg
+		m.pathInput.Blur()
+		m.step = stepMerge
+		return &m, nil
+
+	case exportDone:
+		if msg.err != nil {
+			m.exportErr = msg.err
+			m.merge = nil
+			m.pathInput.Focus()
+			m.step = stepExport
+			return &m, textinput.Blink
+		}
+		m.notice = msg.notice
 		m.step = stepBurned
 		return &m, nil
 	}
@@ -344,7 +457,10 @@ func (m retrieveModel) View() string {
 			m.pathInput.Width = inputWidth
 			b.WriteString(m.pathInput.View())
 			b.WriteString("\n")
-			b.WriteString(Muted.Render("Enter to save • Esc to go back"))
+			if m.exportErr != nil {
+				b.WriteString(Error.Render("Error: "+m.exportErr.Error()) + "\n")
+			}
+			b.WriteString(Muted.Render("Enter to save (existing files are merged) • Esc to go back"))
 		} else {
 			b.WriteString(Success.Render("✓ Secret unlocked.\n\n"))
 			b.WriteString(Box.Width(contentBoxWidth).Render(string(m.plaintext)))
@@ -354,7 +470,33 @@ func (m retrieveModel) View() string {
 			b.WriteString(Muted.Render("Esc to go back"))
 		}
 
+	case stepMerge:
+		p := m.merge
+		b.WriteString(Title.Render("Retrieve env") + "\n\n")
+		b.WriteString(Prompt.Render("Merge into "+p.path+":") + "\n\n")
+		for _, c := range p.changes {
+			switch c.Kind {
+			case dotenv.Added:
+				b.WriteString(Success.Render("  + "+c.Key+"  (added)") + "\n")
+			case dotenv.Changed:
+				b.WriteString(Warning.Render("  ~ "+c.Key+"  (changed)") + "\n")
+			case dotenv.Unchanged:
+				b.WriteString(Muted.Render("  = "+c.Key+"  (unchanged)") + "\n")
+			}
+		}
+		b.WriteString("\n")
+		if n := p.count(dotenv.Changed); n > 0 {
+			b.WriteString(Prompt.Render(fmt.Sprintf("%d key(s) differ. Press ", n)) + "r" + Prompt.Render(" to replace them • ") + "k" + Prompt.Render(" to keep existing values"))
+			b.WriteString("\n")
+			b.WriteString(Muted.Render("A backup is kept as " + p.path + ".bak • Esc to go back"))
+		} else {
+			b.WriteString(Muted.Render("Enter to merge (backup kept as " + p.path + ".bak) • Esc to go back"))
+		}
+
 	case stepBurned:
+		if m.notice != "" {
+			b.WriteString(Success.Render("✓ "+m.notice) + "\n\n")
+		}
 		b.WriteString(Burn.Render("🔥 Secret burned. One-time use complete."))
 		b.WriteString("\n\n")
 		b.WriteString(Muted.Render("Enter or Esc to go back"))
@@ -3,6 +3,7 @@ package ui
 import (
 	"fmt"
 	"os"
+	"slices"
 	"strings"
 	"time"
 
@@ -13,6 +14,9 @@ import (
 	"github.com/charmbracelet/lipgloss"
 	"github.com/yesahem/burnenv/internal/client"
 	"github.com/yesahem/burnenv/internal/crypto"
+	"github.com/yesahem/burnenv/internal/dotenv"
+	"github.com/yesahem/burnenv/internal/expiry"
+	"github.com/yesahem/burnenv/internal/passphrase"
 	"github.com/yesahem/burnenv/internal/store"
 )
 
@@ -20,6 +24,8 @@ type secureStep int
 
 const (
 	secStepSecrets secureStep = iota
+	secStepLoadFile
+	secStepPickKeys
 	secStepPassword
 	secStepMaxViews
 	secStepExpiry
@@ -32,19 +38,33 @@ type secureModel struct {
 	step          secureStep
 	secretInput   textarea.Model
 	passwordInput textinput.Model
+	pathInput     textinput.Model
+	picker        keyPicker
+	loadErr       error
 	maxViews      int
 	maxViewsIdx   int
-	expiryMinutes int
+	viewOptions   []int
+	exp           expiry.Spec
 	expiryIdx     int
+	expiryOptions []int // minutes; the row after them is "Custom"
+	customInput   textinput.Model
+	editingCustom bool
+	customErr     error
+	limits        client.Limits
+	limitsNote    string // Choices reset by late server limits
+	expiresAt     time.Time
 	secrets       string
 	password      string
+	minStrength   int
+	pwErr         error
+	generated     string // Passphrase from Ctrl+G, shown until edited
 	secureKey     string
 	err           error
 	done          bool
 	serverURL     string
 }
 
-func newSecureModel(width, height int) secureModel {
+func newSecureModel(width, height, minStrength int) secureModel {
 	si := textarea.New()
 	si.Placeholder = "Paste your secrets here (multi-line supported)...\nExample:\nAPI_KEY=abc123\nDB_PASSWORD=secret"
 	si.SetWidth(60)
@@ -57,27 +77,153 @@ func newSecureModel(width, height int) secureModel {
 	pi.EchoMode = textinput.EchoPassword
 	pi.Width = 60
 
-	return secureModel{
+	fi := textinput.New()
+	fi.Placeholder = ".env"
+	fi.Width = 60
+
+	ci := textinput.New()
+	ci.Placeholder = "90s, 45m, 6h or 2026-10-17T09:00Z"
+	ci.Width = 60
+
+	m := secureModel{
 		width:         width,
 		height:        height,
 		step:          secStepSecrets,
 		secretInput:   si,
 		passwordInput: pi,
-		maxViews:      1,
-		maxViewsIdx:   0,
-		expiryMinutes: 3,
-		expiryIdx:     1,
+		pathInput:     fi,
+		customInput:   ci,
+		minStrength:   minStrength,
 		serverURL:     os.Getenv("BURNENV_SERVER"),
 	}
+	m.applyLimits(client.DefaultLimits)
+	return m
 }
 
 func (m secureModel) Init() tea.Cmd {
-	return textarea.Blink
+	return tea.Batch(textarea.Blink, m.fetchLimits)
+}
+
+// Candidate selector values; only those within the server's limits are shown.
+var (
+	viewCandidates   = []int{1, 2, 3, 4, 5, 10, 25, 50, 100}
+	expiryCandidates = []int{2, 3, 4, 5, 6, 7, 8, 9, 10, 15, 30, 60, 120, 360, 720, 1440}
+)
+
+type limitsMsg struct{ limits client.Limits }
+
+// fetchLimits asks the target server what it permits (GET /v1/info).
+func (m secureModel) fetchLimits() tea.Msg {
+	return limitsMsg{limits: client.LimitsFor(m.serverURL)}
+}
+
+// applyLimits rebuilds the selector options. Selections still within the
+// new limits are kept; others go back to the defaults (1 viewer, 3
+// minutes), with a note if the user had already made them.
+func (m *secureModel) applyLimits(l client.Limits) {
+	minMin, maxMin := l.ExpiryMinutesRange()
+	first := m.viewOptions == nil
+	custom := !first && m.expiryIdx == len(m.expiryOptions)
+	oldExpiry := 0
+	if !first && !custom {
+		oldExpiry = m.expiryOptions[m.expiryIdx]
+	}
+	m.viewOptions = filterRange(viewCandidates, l.MinMaxViews, l.MaxMaxViews)
+	m.expiryOptions = filterRange(expiryCandidates, minMin, maxMin)
+	m.limits = l
+
+	var reset []string
+	if i := slices.Index(m.viewOptions, m.maxViews); i >= 0 {
+		m.maxViewsIdx = i
+	} else {
+		if !first && m.step > secStepMaxViews {
+			reset = append(reset, "max views")
+		}
+		m.maxViewsIdx = max(slices.Index(m.viewOptions, 1), 0)
+		m.maxViews = m.viewOptions[m.maxViewsIdx]
+	}
+	switch i := slices.Index(m.expiryOptions, oldExpiry); {
+	case custom && l.CheckExpiry(m.exp.Duration(time.Now())) == nil:
+		m.expiryIdx = len(m.expiryOptions)
+	case !custom && i >= 0:
+		m.expiryIdx = i
+	default:
+		if !first && m.step == secStepExpiry {
+			reset = append(reset, "expiry")
+		}
+		m.expiryIdx = max(slices.Index(m.expiryOptions, 3), 0)
+		m.exp = expiry.After(time.Duration(m.expiryOptions[m.expiryIdx]) * time.Minute)
+	}
+	if len(reset) > 0 {
+		m.limitsNote = "The server's limits changed your " + strings.Join(reset, " and ") + "; check before continuing"
+	}
+}
+
+// filterRange keeps candidates within [lo, hi], falling back to lo.
+func filterRange(candidates []int, lo, hi int) []int {
+	var out []int
+	for _, c := range candidates {
+		if c >= lo && c <= hi {
+			out = append(out, c)
+		}
+	}
+	if len(out) == 0 {
+		out = []int{lo}
+	}
+	return out
+}
+
+// formatMinutes renders an expiry option, e.g. "5 minutes" or "2 hours".
+func formatMinutes(min int) string {
+	switch {
+	case min%60 == 0 && min >= 120:
+		return fmt.Sprintf("%d hours", min/60)
+	case min == 60:
+		return "1 hour"
+	default:
+		return fmt.Sprintf("%d minutes", min)
+	}
+}
+
+// StrengthMeter renders a password strength estimate as a bar, e.g.
+// "████░ strong".
+func StrengthMeter(s passphrase.Strength) string {
+	bar := strings.Repeat("█", s.Score+1) + strings.Repeat("░", len(passphrase.Labels)-s.Score-1)
+	style := Success
+	switch {
+	case s.Score <= 1:
+		style = Error
+	case s.Score == 2:
+		style = Warning
+	}
+	return style.Render(bar + " " + s.Label())
+}
+
+// maxLintLines caps the warnings shown under the secrets textarea.
+const maxLintLines = 4
+
+// lintView renders .env problems in secrets as warning lines. Content
+// that isn't a .env at all is still allowed; it is shared as-is.
+func lintView(secrets string) string {
+	warnings, err := dotenv.Lint([]byte(secrets))
+	if err != nil {
+		return "\n" + Warning.Render("⚠ Not a valid .env ("+err.Error()+"); it will be shared as plain text")
+	}
+	var b strings.Builder
+	for i, w := range warnings {
+		if i == maxLintLines {
+			b.WriteString("\n" + Warning.Render(fmt.Sprintf("⚠ …and %d more", len(warnings)-i)))
+			break
+		}
+		b.WriteString("\n" + Warning.Render("⚠ "+w.String()))
+	}
+	return b.String()
 }
 
 type secureResult struct {
-	key string
-	err error
+	key       string
+	expiresAt time.Time
+	err       error
 }
 
 func (m secureModel) doCreate() tea.Msg {
@@ -94,8 +240,8 @@ func (m secureModel) doCreate() tea.Msg {
 		return secureResult{err: err}
 	}
 
-	expiry := time.Now().Add(time.Duration(m.expiryMinutes) * time.Minute).Unix()
-	payload.Expiry = expiry
+	expiresAt := m.exp.At(time.Now())
+	payload.Expiry = expiresAt.Unix()
 	payload.MaxViews = m.maxViews
 
 	var key string
@@ -111,7 +257,7 @@ func (m secureModel) doCreate() tea.Msg {
 	if err != nil {
 		return secureResult{err: err}
 	}
-	return secureResult{key: key}
+	return secureResult{key: key, expiresAt: expiresAt}
 }
 
 func (m secureModel) inputWidth() int {
@@ -130,11 +276,30 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		w := m.inputWidth()
 		m.secretInput.SetWidth(w)
 		m.passwordInput.Width = w
+		m.pathInput.Width = w
+		m.customInput.Width = w
 		return &m, nil
 
 	case tea.KeyMsg:
 		keyStr := msg.String()
 
+		// Esc while typing a custom expiry goes back to the list
+		if keyStr == "esc" && m.step == secStepExpiry && m.editingCustom {
+			m.editingCustom = false
+			m.customErr = nil
+			m.customInput.Blur()
+			return &m, nil
+		}
+
+		// Esc while loading a file goes back to the text area, not the menu
+		if keyStr == "esc" && (m.step == secStepLoadFile || m.step == secStepPickKeys) {
+			m.loadErr = nil
+			m.pathInput.Blur()
+			m.secretInput.Focus()
+			m.step = secStepSecrets
+			return &m, textarea.Blink
+		}
+
 		// Handle global keys first
 		switch keyStr {
 		case "ctrl+c":
@@ -160,6 +325,33 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Space detection for max-views step
 		isSpace := msg.Type == tea.KeySpace || keyStr == " " || keyStr == "space"
 
+		if m.step == secStepLoadFile && isEnter {
+			return &m, m.loadFile()
+		}
+
+		// Key picker: Enter shares the selected keys
+		if m.step == secStepPickKeys {
+			if !isEnter {
+				m.picker.update(keyStr)
+				return &m, nil
+			}
+			chosen := m.picker.chosen()
+			if len(chosen) == 0 {
+				m.loadErr = fmt.Errorf("select at least one key")
+				return &m, nil
+			}
+			secrets, err := dotenv.Render(chosen, dotenv.FormatDotenv, "")
+			if err != nil {
+				m.loadErr = err
+				return &m, nil
+			}
+			m.secrets = string(secrets)
+			m.loadErr = nil
+			m.passwordInput.Focus()
+			m.step = secStepPassword
+			return &m, textinput.Blink
+		}
+
 		// Handle max-views step FIRST before any input forwarding
 		if m.step == secStepMaxViews {
 			// Confirm with Enter or Space -> advance to expiry step
@@ -172,20 +364,18 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "up", "k":
 				if m.maxViewsIdx > 0 {
 					m.maxViewsIdx--
-					m.maxViews = m.maxViewsIdx + 1
 				}
-				return &m, nil
 			case "down", "j":
-				if m.maxViewsIdx < 4 {
+				if m.maxViewsIdx < len(m.viewOptions)-1 {
 					m.maxViewsIdx++
-					m.maxViews = m.maxViewsIdx + 1
 				}
-				return &m, nil
-			case "1", "2", "3", "4", "5":
-				m.maxViews = int(keyStr[0] - '0')
-				m.maxViewsIdx = m.maxViews - 1
-				return &m, nil
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				// Digits pick the nth option
+				if i := int(keyStr[0] - '1'); i < len(m.viewOptions) {
+					m.maxViewsIdx = i
+				}
 			}
+			m.maxViews = m.viewOptions[m.maxViewsIdx]
 			// Ctrl+S also confirms -> advance to expiry step
 			if keyStr == "ctrl+s" {
 				m.step = secStepExpiry
@@ -196,7 +386,21 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Handle expiry step
 		if m.step == secStepExpiry {
-			// Confirm with Enter or Space -> create the secret
+			if m.editingCustom {
+				if !isEnter {
+					var cmd tea.Cmd
+					m.customInput, cmd = m.customInput.Update(msg)
+					return &m, cmd
+				}
+				return &m, m.setCustomExpiry()
+			}
+			custom := m.expiryIdx == len(m.expiryOptions)
+			// Confirm with Enter or Space -> create the secret, or type a custom expiry
+			if (isEnter || isSpace) && custom {
+				m.editingCustom = true
+				m.customInput.Focus()
+				return &m, textinput.Blink
+			}
 			if isEnter || isSpace {
 				return &m, m.doCreate
 			}
@@ -205,32 +409,22 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "up", "k":
 				if m.expiryIdx > 0 {
 					m.expiryIdx--
-					m.expiryMinutes = m.expiryIdx + 2
 				}
-				return &m, nil
 			case "down", "j":
-				if m.expiryIdx < 8 {
+				if m.expiryIdx < len(m.expiryOptions) {
 					m.expiryIdx++
-					m.expiryMinutes = m.expiryIdx + 2
 				}
-				return &m, nil
-			case "2", "3", "4", "5", "6", "7", "8", "9":
-				m.expiryMinutes = int(keyStr[0] - '0')
-				m.expiryIdx = m.expiryMinutes - 2
-				return &m, nil
-			case "0":
-				// 0 selects 10 minutes
-				m.expiryMinutes = 10
-				m.expiryIdx = 8
-				return &m, nil
-			case "1":
-				// 1+0 = 10; just set to 10 for convenience
-				m.expiryMinutes = 10
-				m.expiryIdx = 8
-				return &m, nil
+			case "1", "2", "3", "4", "5", "6", "7", "8", "9":
+				// Digits pick the nth option
+				if i := int(keyStr[0] - '1'); i < len(m.expiryOptions) {
+					m.expiryIdx = i
+				}
+			}
+			if m.expiryIdx < len(m.expiryOptions) {
+				m.exp = expiry.After(time.Duration(m.expiryOptions[m.expiryIdx]) * time.Minute)
 			}
 			// Ctrl+S also confirms
-			if keyStr == "ctrl+s" {
+			if keyStr == "ctrl+s" && m.expiryIdx < len(m.expiryOptions) {
 				return &m, m.doCreate
 			}
 			return &m, nil
@@ -245,8 +439,26 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return &m, nil
 		}
 
+		// Ctrl+G fills in a generated passphrase
+		if m.step == secStepPassword && keyStr == "ctrl+g" {
+			phrase, err := passphrase.Generate(passphrase.DefaultWords)
+			if err != nil {
+				m.err = err
+				return &m, nil
+			}
+			m.passwordInput.SetValue(phrase)
+			m.passwordInput.CursorEnd()
+			m.generated = phrase
+			return &m, nil
+		}
+
 		// Handle password step - Enter advances
 		if m.step == secStepPassword && isEnter {
+			if s := passphrase.Estimate([]byte(m.passwordInput.Value())); s.Score < m.minStrength {
+				m.pwErr = fmt.Errorf("password is %s; at least %s is required", s.Label(), passphrase.Labels[m.minStrength])
+				return &m, nil
+			}
+			m.pwErr = nil
 			m.password = m.passwordInput.Value()
 			m.passwordInput.Blur()
 			m.step = secStepMaxViews
@@ -262,14 +474,27 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.step = secStepPassword
 				return &m, textinput.Blink
 			}
+			if keyStr == "ctrl+o" {
+				m.secretInput.Blur()
+				m.pathInput.Focus()
+				m.step = secStepLoadFile
+				return &m, textinput.Blink
+			}
 		}
 
+	case limitsMsg:
+		if m.step != secStepResult { // Too late to matter
+			m.applyLimits(msg.limits)
+		}
+		return &m, nil
+
 	case secureResult:
 		if msg.err != nil {
 			m.err = msg.err
 			return &m, nil
 		}
 		m.secureKey = msg.key
+		m.expiresAt = msg.expiresAt
 		m.step = secStepResult
 		return &m, nil
 	}
@@ -285,10 +510,61 @@ func (m secureModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		m.passwordInput, cmd = m.passwordInput.Update(msg)
 		return &m, cmd
 	}
+	if m.step == secStepLoadFile {
+		var cmd tea.Cmd
+		m.pathInput, cmd = m.pathInput.Update(msg)
+		return &m, cmd
+	}
 
 	return &m, nil
 }
 
+// setCustomExpiry parses the custom expiry and creates the secret, or
+// shows why it isn't accepted.
+func (m *secureModel) setCustomExpiry() tea.Cmd {
+	exp, err := expiry.Parse(m.customInput.Value(), time.Now())
+	if err == nil {
+		err = m.limits.CheckExpiry(exp.Duration(time.Now()))
+	}
+	if err != nil {
+		m.customErr = err
+		return nil
+	}
+	m.exp = exp
+	m.customErr = nil
+	m.editingCustom = false
+	m.customInput.Blur()
+	return m.doCreate
+}
+
+// loadFile parses the .env at the entered path (default .env) and opens
+// the key picker, or shows why it can't.
+func (m *secureModel) loadFile() tea.Cmd {
+	path := strings.TrimSpace(m.pathInput.Value())
+	if path == "" {
+		path = ".env"
+	}
+	data, err := os.ReadFile(path)
+	if err != nil {
+		m.loadErr = err
+		return nil
+	}
+	entries, err := dotenv.Parse(data)
+	if err != nil {
+		m.loadErr = fmt.Errorf("%s: %w", path, err)
+		return nil
+	}
+	if len(entries) == 0 {
+		m.loadErr = fmt.Errorf("%s has no keys", path)
+		return nil
+	}
+	m.picker = newKeyPicker(path, entries)
+	m.loadErr = nil
+	m.pathInput.Blur()
+	m.step = secStepPickKeys
+	return nil
+}
+
 func (m secureModel) View() string {
 	// Use most of the terminal width
 	boxWidth := m.width - 8
@@ -320,9 +596,30 @@ func (m secureModel) View() string {
 		if secretLen > 0 {
 			b.WriteString("\n")
 			b.WriteString(Success.Render(fmt.Sprintf("✓ %d characters, %d line(s)", secretLen, lines)))
+			b.WriteString(lintView(m.secretInput.Value()))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(Muted.Render("Ctrl+S to continue • Ctrl+O to pick keys from a .env file • Esc to cancel"))
+
+	case secStepLoadFile:
+		b.WriteString(Title.Render("Secure env") + "\n\n")
+		b.WriteString(Prompt.Render("Load keys from a .env file:") + "\n")
+		b.WriteString(m.pathInput.View())
+		if m.loadErr != nil {
+			b.WriteString("\n" + Error.Render("Error: "+m.loadErr.Error()))
 		}
 		b.WriteString("\n\n")
-		b.WriteString(Muted.Render("Ctrl+S to continue • Esc to cancel"))
+		b.WriteString(Muted.Render("Enter to load • Esc to go back"))
+
+	case secStepPickKeys:
+		b.WriteString(Title.Render("Secure env") + "\n\n")
+		b.WriteString(Prompt.Render("Keys to share from "+m.picker.source+":") + "\n\n")
+		b.WriteString(m.picker.view())
+		if m.loadErr != nil {
+			b.WriteString("\n" + Error.Render("Error: "+m.loadErr.Error()))
+		}
+		b.WriteString("\n\n")
+		b.WriteString(Muted.Render("↑/↓ to move • Space to toggle • a for all/none • Enter to continue • Esc to go back"))
 
 	case secStepPassword:
 		b.WriteString(Title.Render("Secure env") + "\n\n")
@@ -332,44 +629,74 @@ func (m secureModel) View() string {
 		if pwLen > 0 {
 			b.WriteString("\n")
 			b.WriteString(Success.Render("✓ " + strings.Repeat("●", pwLen) + " (" + fmt.Sprintf("%d", pwLen) + " chars)"))
+			strength := passphrase.Estimate([]byte(m.passwordInput.Value()))
+			b.WriteString("\n" + StrengthMeter(strength))
+			if strength.Warning != "" {
+				b.WriteString("\n" + Warning.Render("⚠ "+strength.Warning))
+			}
+			if m.generated != "" && m.passwordInput.Value() == m.generated {
+				b.WriteString("\n" + Link.Render(m.generated))
+				b.WriteString("\n" + Muted.Render("Share this passphrase separately from the key"))
+			}
+		}
+		if m.pwErr != nil {
+			b.WriteString("\n" + Error.Render("Error: "+m.pwErr.Error()))
 		}
 		b.WriteString("\n\n")
-		b.WriteString(Muted.Render("Enter to continue • Esc to cancel"))
+		b.WriteString(Muted.Render("Enter to continue • Ctrl+G to generate a passphrase • Esc to cancel"))
 
 	case secStepMaxViews:
 		b.WriteString(Title.Render("Secure env") + "\n\n")
-		b.WriteString(Prompt.Render("Max views (1-5):") + "\n\n")
-		for i := 1; i <= 5; i++ {
+		b.WriteString(Prompt.Render(fmt.Sprintf("Max views (%d-%d):", m.viewOptions[0], m.viewOptions[len(m.viewOptions)-1])) + "\n\n")
+		for i, n := range m.viewOptions {
 			marker := "  "
-			if i == m.maxViews {
+			if i == m.maxViewsIdx {
 				marker = "> "
 			}
-			if i == m.maxViews {
-				b.WriteString(Focused.Render(marker+fmt.Sprintf("%d person(s) can view", i)) + "\n")
+			if i == m.maxViewsIdx {
+				b.WriteString(Focused.Render(marker+fmt.Sprintf("%d person(s) can view", n)) + "\n")
 			} else {
-				b.WriteString(Prompt.Render(marker+fmt.Sprintf("%d person(s) can view", i)) + "\n")
+				b.WriteString(Prompt.Render(marker+fmt.Sprintf("%d person(s) can view", n)) + "\n")
 			}
 		}
 		b.WriteString("\n")
-		b.WriteString(Muted.Render("↑/↓ or 1-5 to select • Enter or Space to continue"))
+		b.WriteString(Muted.Render(fmt.Sprintf("↑/↓ or 1-%d to select • Enter or Space to continue", min(len(m.viewOptions), 9))))
 
 	case secStepExpiry:
 		b.WriteString(Title.Render("Secure env") + "\n\n")
-		b.WriteString(Prompt.Render("Auto-expiry time (2-10 min):") + "\n\n")
-		for i := 2; i <= 10; i++ {
+		if m.limitsNote != "" {
+			b.WriteString(Warning.Render("⚠ "+m.limitsNote) + "\n\n")
+		}
+		first, last := m.expiryOptions[0], m.expiryOptions[len(m.expiryOptions)-1]
+		b.WriteString(Prompt.Render(fmt.Sprintf("Auto-expiry time (%s - %s):", formatMinutes(first), formatMinutes(last))) + "\n\n")
+		for i, n := range m.expiryOptions {
 			marker := "  "
-			if i == m.expiryMinutes {
+			if i == m.expiryIdx {
 				marker = "> "
 			}
-			label := fmt.Sprintf("%d minutes", i)
-			if i == m.expiryMinutes {
+			label := formatMinutes(n)
+			if i == m.expiryIdx {
 				b.WriteString(Focused.Render(marker+label) + "\n")
 			} else {
 				b.WriteString(Prompt.Render(marker+label) + "\n")
 			}
 		}
-		b.WriteString("\n")
-		b.WriteString(Muted.Render("↑/↓ or 2-9,0 to select • Enter or Space to generate"))
+		switch {
+		case m.editingCustom:
+			b.WriteString(Focused.Render("> Custom:") + "\n")
+			b.WriteString(m.customInput.View())
+			if m.customErr != nil {
+				b.WriteString("\n" + Error.Render("Error: "+m.customErr.Error()))
+			}
+			b.WriteString("\n\n")
+			b.WriteString(Muted.Render("Duration or time • Enter to generate • Esc to go back"))
+		case m.expiryIdx == len(m.expiryOptions):
+			b.WriteString(Focused.Render("> Custom…") + "\n\n")
+			b.WriteString(Muted.Render("Enter to type a duration or time"))
+		default:
+			b.WriteString(Prompt.Render("  Custom…") + "\n\n")
+			b.WriteString(Muted.Render(fmt.Sprintf("↑/↓ or 1-%d to select • Enter or Space to generate", min(len(m.expiryOptions), 9))))
+		}
 
 	case secStepResult:
 		if m.err != nil {
@@ -379,7 +706,11 @@ func (m secureModel) View() string {
 			b.WriteString(Success.Render("✓ Secrets secured.\n\n"))
 			b.WriteString("Secure key:\n")
 			b.WriteString(Link.Render(m.secureKey) + "\n\n")
-			b.WriteString(Muted.Render(fmt.Sprintf("Expires in %d min • Share with up to %d person(s). Enter or Esc to go back", m.expiryMinutes, m.maxViews)))
+			if m.generated != "" && m.password == m.generated {
+				b.WriteString("Passphrase (share separately):\n")
+				b.WriteString(Link.Render(m.generated) + "\n\n")
+			}
+			b.WriteString(Muted.Render(fmt.Sprintf("Expires %s • Share with up to %d person(s). Enter or Esc to go back", expiry.Describe(m.expiresAt, time.Now()), m.maxViews)))
 		}
 	}
 
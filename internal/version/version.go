@@ -0,0 +1,7 @@
+// Package version holds the build version, set at link time:
+//
+//	go build -ldflags "-X github.com/yesahem/burnenv/internal/version.Version=v1.2.3"
+package version
+
+// Version is the BurnEnv release version ("dev" for local builds).
+var Version = "dev"
@@ -0,0 +1,35 @@
+//go:build js && wasm
+
+// Command argon2wasm exposes Argon2id to the browser retrieval page.
+// Built by go generate in internal/web; the output is embedded there.
+package main
+
+import (
+	"syscall/js"
+
+	"golang.org/x/crypto/argon2"
+)
+
+// burnenvArgon2id(password, salt Uint8Array, time, memory, threads, keyLen) Uint8Array
+func derive(this js.Value, args []js.Value) any {
+	if len(args) != 6 {
+		return js.Null()
+	}
+	password := make([]byte, args[0].Length())
+	js.CopyBytesToGo(password, args[0])
+	salt := make([]byte, args[1].Length())
+	js.CopyBytesToGo(salt, args[1])
+
+	key := argon2.IDKey(password, salt, uint32(args[2].Int()), uint32(args[3].Int()), uint8(args[4].Int()), uint32(args[5].Int()))
+	clear(password)
+
+	out := js.Global().Get("Uint8Array").New(len(key))
+	js.CopyBytesToJS(out, key)
+	clear(key)
+	return out
+}
+
+func main() {
+	js.Global().Set("burnenvArgon2id", js.FuncOf(derive))
+	select {} // Keep the exported function alive
+}
@@ -0,0 +1,107 @@
+// Retrieves, decrypts and burns a BurnEnv drop entirely in the browser.
+// Mirrors internal/crypto: Argon2id (WASM) -> AES-256-GCM (WebCrypto).
+"use strict";
+
+(function () {
+  const script = document.currentScript;
+  const id = decodeURIComponent(location.pathname.split("/").pop());
+  const form = document.getElementById("unlock");
+  const status = document.getElementById("status");
+  const result = document.getElementById("result");
+  const secret = document.getElementById("secret");
+  const download = document.getElementById("download");
+
+  function setStatus(text, isError) {
+    status.textContent = text;
+    status.className = isError ? "error" : "muted";
+  }
+
+  function fromBase64(s) {
+    const bin = atob(s);
+    const out = new Uint8Array(bin.length);
+    for (let i = 0; i < bin.length; i++) out[i] = bin.charCodeAt(i);
+    return out;
+  }
+
+  let wasmReady = null;
+  function loadArgon2() {
+    if (!wasmReady) {
+      const go = new Go();
+      wasmReady = fetch("/assets/argon2.wasm", { integrity: script.dataset.wasmSri })
+        .then((resp) => WebAssembly.instantiateStreaming(resp, go.importObject))
+        .then((mod) => { go.run(mod.instance); });
+    }
+    return wasmReady;
+  }
+
+  async function retrieve(password) {
+    // Fetching consumes a view, so it only happens after the user asks
+    const resp = await fetch("/v1/drop/" + encodeURIComponent(id), { cache: "no-store" });
+    const body = await resp.json().catch(() => ({}));
+    if (!resp.ok) {
+      throw new Error(body.error || "server returned " + resp.status);
+    }
+    if (!body.kdf || body.kdf.algorithm !== "argon2id") {
+      throw new Error("unsupported key derivation: " + (body.kdf && body.kdf.algorithm));
+    }
+
+    setStatus("Deriving key (this takes a few seconds)...");
+    await loadArgon2();
+    const pw = new TextEncoder().encode(password);
+    const raw = burnenvArgon2id(pw, fromBase64(body.salt), body.kdf.time, body.kdf.memory, body.kdf.threads, 32);
+    pw.fill(0);
+
+    const key = await crypto.subtle.importKey("raw", raw, "AES-GCM", false, ["decrypt"]);
+    raw.fill(0);
+    // A content type is authenticated as additional data, like the CLI does
+    const params = { name: "AES-GCM", iv: fromBase64(body.iv) };
+    if (body.content_type) {
+      params.additionalData = new TextEncoder().encode(body.content_type);
+    }
+    let plain;
+    try {
+      plain = await crypto.subtle.decrypt(params, key, fromBase64(body.ciphertext));
+    } catch (e) {
+      throw new Error("decryption failed (wrong password or corrupted data)");
+    }
+    return { contentType: body.content_type || "", data: plain };
+  }
+
+  // show displays text secrets; shared files are offered as a download.
+  function show(plain) {
+    if (!plain.contentType) {
+      secret.textContent = new TextDecoder().decode(plain.data);
+      return;
+    }
+    const blob = new Blob([plain.data], { type: plain.contentType });
+    download.href = URL.createObjectURL(blob);
+    download.textContent = "Download files (" + blob.size + " bytes)";
+    download.hidden = false;
+    secret.hidden = true;
+    document.getElementById("copy").hidden = true;
+  }
+
+  form.addEventListener("submit", async (ev) => {
+    ev.preventDefault();
+    const input = document.getElementById("password");
+    const button = document.getElementById("reveal");
+    button.disabled = true;
+    setStatus("Retrieving...");
+    try {
+      show(await retrieve(input.value));
+      input.value = "";
+      form.hidden = true;
+      result.hidden = false;
+      setStatus("🧨 Secret retrieved. This link may no longer work.");
+    } catch (e) {
+      setStatus(e.message, true);
+      button.disabled = false;
+    }
+  });
+
+  document.getElementById("copy").addEventListener("click", () => {
+    navigator.clipboard.writeText(secret.textContent)
+      .then(() => setStatus("Copied to clipboard."))
+      .catch(() => setStatus("Copy failed; select the text instead.", true));
+  });
+})();
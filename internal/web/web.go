@@ -0,0 +1,96 @@
+// Package web serves the browser retrieval page at /d/{id}, for recipients
+// without the CLI. The page fetches the encrypted blob and decrypts it
+// locally (Argon2id via WASM, AES-GCM via WebCrypto), so the server still
+// never sees the password or plaintext.
+package web
+
+//go:generate sh -c "GOOS=js GOARCH=wasm go build -trimpath -ldflags='-s -w' -o static/argon2.wasm ./argon2wasm"
+//go:generate sh -c "cp \"$(go env GOROOT)/lib/wasm/wasm_exec.js\" static/"
+
+import (
+	"bytes"
+	"crypto/sha512"
+	"embed"
+	"encoding/base64"
+	"html/template"
+	"io/fs"
+	"net/http"
+)
+
+// CSP locks the page down to its own embedded assets. WASM compilation
+// needs 'wasm-unsafe-eval'; JavaScript eval stays forbidden.
+const CSP = "default-src 'none'; script-src 'self' 'wasm-unsafe-eval'; style-src 'self'; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+//go:embed static
+var static embed.FS
+
+// assets is the static directory served under /assets/.
+var assets, _ = fs.Sub(static, "static")
+
+// SRI returns the Subresource Integrity value (sha384) of an embedded asset.
+func SRI(name string) (string, error) {
+	data, err := fs.ReadFile(assets, name)
+	if err != nil {
+		return "", err
+	}
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:]), nil
+}
+
+// page is index.html with the integrity hashes of the embedded assets.
+var page = mustRenderPage()
+
+// Routes serves GET /d/{id} and GET /assets/ and passes every other request
+// to next. The page never touches the drop: it is only fetched (and burned)
+// when the recipient clicks reveal, so link previews are harmless.
+func Routes(next http.Handler) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /d/{id}", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Security-Policy", CSP)
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		w.Header().Set("Cache-Control", "no-store")
+		w.Header().Set("Referrer-Policy", "no-referrer")
+		w.Write(page)
+	})
+	files := http.StripPrefix("/assets/", http.FileServerFS(assets))
+	mux.HandleFunc("GET /assets/", func(w http.ResponseWriter, r *http.Request) {
+		// No directory listing, and the page template is only served rendered
+		if name := r.URL.Path[len("/assets/"):]; name == "" || name == "index.html" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Header().Set("Content-Security-Policy", CSP)
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		files.ServeHTTP(w, r)
+	})
+	mux.Handle("/", next)
+	return mux
+}
+
+// mustRenderPage fills the integrity attributes of index.html. The inputs
+// are embedded, so a failure is a build problem.
+func mustRenderPage() []byte {
+	tmpl := template.Must(template.ParseFS(assets, "index.html"))
+	var data struct{ StyleSRI, WasmExecSRI, RetrieveSRI, WasmSRI string }
+	for _, a := range []struct {
+		name string
+		dst  *string
+	}{
+		{"style.css", &data.StyleSRI},
+		{"wasm_exec.js", &data.WasmExecSRI},
+		{"retrieve.js", &data.RetrieveSRI},
+		{"argon2.wasm", &data.WasmSRI},
+	} {
+		sri, err := SRI(a.name)
+		if err != nil {
+			panic(err)
+		}
+		*a.dst = sri
+	}
+	var buf bytes.Buffer
+	if err := tmpl.Execute(&buf, data); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
@@ -0,0 +1,112 @@
+package web
+
+import (
+	"crypto/sha512"
+	"encoding/base64"
+	"html"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path"
+	"regexp"
+	"testing"
+)
+
+// wantCSP is spelled out rather than taken from CSP, so loosening the
+// policy has to change this test too.
+const wantCSP = "default-src 'none'; script-src 'self' 'wasm-unsafe-eval'; style-src 'self'; " +
+	"connect-src 'self'; base-uri 'none'; form-action 'none'; frame-ancestors 'none'"
+
+func get(t *testing.T, h http.Handler, target string) *httptest.ResponseRecorder {
+	t.Helper()
+	rec := httptest.NewRecorder()
+	h.ServeHTTP(rec, httptest.NewRequest("GET", target, nil))
+	return rec
+}
+
+// sri computes a Subresource Integrity value independently of SRI.
+func sri(data []byte) string {
+	sum := sha512.Sum384(data)
+	return "sha384-" + base64.StdEncoding.EncodeToString(sum[:])
+}
+
+func TestPageHeaders(t *testing.T) {
+	rec := get(t, Routes(http.NotFoundHandler()), "/d/0123456789abcdef0123456789abcdef")
+	if rec.Code != http.StatusOK {
+		t.Fatalf("status %d", rec.Code)
+	}
+	for name, want := range map[string]string{
+		"Content-Security-Policy": wantCSP,
+		"Content-Type":            "text/html; charset=utf-8",
+		"Cache-Control":           "no-store",
+		"Referrer-Policy":         "no-referrer",
+	} {
+		if got := rec.Header().Get(name); got != want {
+			t.Errorf("%s = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// assetRef matches the integrity-pinned assets of index.html: stylesheet
+// and scripts by integrity=, the WASM module by data-wasm-sri=.
+var assetRef = regexp.MustCompile(`(?:href|src)="/assets/([^"]+)" integrity="([^"]+)"`)
+var wasmRef = regexp.MustCompile(`data-wasm-sri="([^"]+)"`)
+
+func TestAssetIntegrity(t *testing.T) {
+	h := Routes(http.NotFoundHandler())
+	page := get(t, h, "/d/x").Body.String()
+
+	pinned := map[string]string{}
+	for _, m := range assetRef.FindAllStringSubmatch(page, -1) {
+		pinned[m[1]] = html.UnescapeString(m[2]) // As a browser reads it
+	}
+	if m := wasmRef.FindStringSubmatch(page); m != nil {
+		pinned["argon2.wasm"] = html.UnescapeString(m[1])
+	}
+	for _, name := range []string{"style.css", "wasm_exec.js", "retrieve.js", "argon2.wasm"} {
+		if _, ok := pinned[name]; !ok {
+			t.Errorf("index.html does not pin %s", name)
+		}
+	}
+
+	for name, integrity := range pinned {
+		t.Run(name, func(t *testing.T) {
+			src, err := os.ReadFile(path.Join("static", name))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if want := sri(src); integrity != want {
+				t.Errorf("integrity %q, want %q", integrity, want)
+			}
+
+			rec := get(t, h, "/assets/"+name)
+			if rec.Code != http.StatusOK {
+				t.Fatalf("status %d", rec.Code)
+			}
+			if got := sri(rec.Body.Bytes()); got != integrity {
+				t.Errorf("served asset hashes to %q, page pins %q", got, integrity)
+			}
+			if got := rec.Header().Get("Content-Security-Policy"); got != wantCSP {
+				t.Errorf("Content-Security-Policy = %q, want %q", got, wantCSP)
+			}
+			if got := rec.Header().Get("X-Content-Type-Options"); got != "nosniff" {
+				t.Errorf("X-Content-Type-Options = %q, want nosniff", got)
+			}
+		})
+	}
+}
+
+func TestAssetsNotListed(t *testing.T) {
+	h := Routes(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTeapot)
+	}))
+	for target, want := range map[string]int{
+		"/assets/":           http.StatusNotFound,
+		"/assets/index.html": http.StatusNotFound,
+		"/v1/info":           http.StatusTeapot, // Passed to next
+	} {
+		if got := get(t, h, target).Code; got != want {
+			t.Errorf("GET %s: status %d, want %d", target, got, want)
+		}
+	}
+}